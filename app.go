@@ -6,72 +6,244 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/ugozlave/cargo"
 )
 
+const ShutdownTimeout = 30 * time.Second
+
 type App struct {
-	config    *AppConfig
-	container *cargo.Container
+	config        *AppConfig
+	container     *cargo.Container
+	lifecycle     lifecycle
+	registrations []*registration
+	sequence      int
+	services      map[service][]*registration
+	decorators    map[service][]func(*BuilderContext, any) any
+	conflicts     sync.Map
+	scopes        sync.Map
+	installed     map[moduleKey]bool
+	modules       []string
+	module        string
+	options       *options
+}
+
+func Empty(cfg *AppConfig, opts ...Option) *App {
+	return empty(cfg, new_options(false, opts))
 }
 
-func Empty(cfg *AppConfig) *App {
+func empty(cfg *AppConfig, o *options) *App {
 	ctn := cargo.New()
+	ctn.Register(disposerKey, disposerKey, disposer_builder(ctn))
 	return &App{
 		config:    cfg.Default(),
 		container: ctn,
+		options:   o,
 	}
 }
 
-func (app *App) Run(ctx context.Context) {
+func (app *App) Run(ctx context.Context) error {
 
 	cfg := app.config
 
-	ctx = context.WithValue(ctx, CtxName, cfg.Name)
+	ctx = app.context(ctx)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
 	ctn := app.container
-	defer ctn.Close()
+	defer app.Close()
 
-	ctn.CreateScope(fmt.Sprintf(ScopeApplicationKeyFormat, cfg.Name))
+	bctx := NewBuilderContext(ctx, ctn)
+	create_scope(bctx, fmt.Sprintf(ScopeApplicationKeyFormat, cfg.Name))
+
+	if err := app.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	logger := app.logger(bctx)
 
 	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
 	server := http.Server{
 		Addr:        addr,
-		Handler:     &HttpInjector{ctn: ctn, gen: MustGet[UniqueIDGenerator](NewBuilderContext(context.TODO(), ctn), Transient)},
+		Handler:     app.Handler(),
 		BaseContext: func(net.Listener) context.Context { return ctx },
 	}
 
-	if SETTINGS.DEBUG {
+	if app.options.settings.DEBUG {
 		app.Inspect()
 	}
 
-	fmt.Printf("server start [%v]\n", addr)
+	if err := app.lifecycle.start(bctx, logger, PhaseStarting); err != nil {
+		return fmt.Errorf("%s hook failed: %w", PhaseStarting, err)
+	}
 
-	go func() {
-		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
-			panic(err)
+	var errs []error
+	fail := func(err error) {
+		errs = append(errs, err)
+		cancel()
+	}
+
+	failed := make(chan error, 1)
+
+	services, err := start_services(ctx, logger, All[HostedService](bctx))
+	if err != nil {
+		fail(fmt.Errorf("hosted service start failed: %w", err))
+	} else if listener, err := net.Listen("tcp", addr); err != nil {
+		fail(fmt.Errorf("server listen failed: %w", err))
+	} else {
+		logger.Inf("server start", LogAddress, addr)
+
+		watch_services(ctx, logger, services, failed)
+
+		go func() {
+			if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+				failed <- fmt.Errorf("server failed: %w", err)
+			}
+		}()
+
+		if err := app.lifecycle.start(bctx, logger, PhaseStarted); err != nil {
+			fail(fmt.Errorf("%s hook failed: %w", PhaseStarted, err))
 		}
-	}()
+	}
 
-	<-ctx.Done()
+	select {
+	case <-ctx.Done():
+	case err := <-failed:
+		fail(err)
+	}
 
-	fmt.Println()
+	timeout, release := context.WithTimeout(context.WithoutCancel(ctx), app.options.shutdown)
+	defer release()
 
-	timeout, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	sctx := NewBuilderContext(timeout, ctn)
+
+	if err := app.lifecycle.stop(sctx, logger, PhaseStopping); err != nil {
+		errs = append(errs, fmt.Errorf("%s hook failed: %w", PhaseStopping, err))
+	}
 
 	if err := server.Shutdown(timeout); err != nil {
-		fmt.Println("server shutdown failed:", err.Error())
+		errs = append(errs, fmt.Errorf("server shutdown failed: %w", err))
+	}
+
+	if err := stop_services(timeout, logger, services); err != nil {
+		errs = append(errs, fmt.Errorf("hosted service stop failed: %w", err))
 	}
 
-	fmt.Println("server stop")
+	if err := app.lifecycle.stop(sctx, logger, PhaseStopped); err != nil {
+		errs = append(errs, fmt.Errorf("%s hook failed: %w", PhaseStopped, err))
+	}
 
+	logger.Inf("server stop", LogAddress, addr)
+
+	return errors.Join(errs...)
 }
 
-func (app *App) Inspect() {
+func (app *App) Validate() error {
 	ctn := app.container
-	ctn.Inspect()
+	name := app.config.Name
+
+	ctx := context.WithValue(app.context(context.Background()), CtxRequestId, ScopeValidationId)
+	bctx := NewBuilderContext(ctx, ctn)
+
+	create_scope(bctx, fmt.Sprintf(ScopeApplicationKeyFormat, name))
+
+	scope := fmt.Sprintf(ScopeRequestKeyFormat, ScopeValidationId)
+	create_scope(bctx, scope)
+	defer delete_scope(bctx, scope)
+
+	var errs []error
+	if !app.has(service{key: From[UniqueIDGenerator]()}) {
+		errs = append(errs, fmt.Errorf("%v: not registered", From[UniqueIDGenerator]()))
+	}
+	for _, reg := range app.registrations {
+		if err := reg.validate(bctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (app *App) Handler() http.Handler {
+	ctn := app.container
+	ctx := app.context(context.Background())
+	bctx := NewBuilderContext(ctx, ctn)
+	create_scope(bctx, fmt.Sprintf(ScopeApplicationKeyFormat, app.config.Name))
+	return &HttpInjector{
+		app:     app,
+		ctn:     ctn,
+		gen:     MustGet[UniqueIDGenerator](bctx, Singleton),
+		handler: app.compile(ctx),
+	}
+}
+
+func (app *App) Close() {
+	// request scopes first, their disposables may still use singletons
+	root := fmt.Sprintf(ScopeApplicationKeyFormat, app.config.Name)
+	app.scopes.Range(func(scope, _ any) bool {
+		if scope != root {
+			app.container.DeleteScope(scope.(string))
+		}
+		return true
+	})
+	app.scopes.Clear()
+	app.container.Close()
+}
+
+func (app *App) NewScope(ctx context.Context) (*BuilderContext, func()) {
+	ctn := app.container
+	name := app.config.Name
+
+	ctx = app.context(ctx)
+	root := NewBuilderContext(ctx, ctn)
+	create_scope(root, fmt.Sprintf(ScopeApplicationKeyFormat, name))
+
+	id := MustGet[UniqueIDGenerator](root, Singleton).Next()
+	bctx := NewBuilderContext(context.WithValue(ctx, CtxRequestId, id), ctn)
+
+	scope := fmt.Sprintf(ScopeRequestKeyFormat, id)
+	create_scope(bctx, scope)
+
+	var once sync.Once
+	return bctx, func() {
+		once.Do(func() {
+			delete_scope(bctx, scope)
+		})
+	}
+}
+
+func (app *App) context(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, CtxName, app.config.Name)
+	return context.WithValue(ctx, CtxApp, app)
+}
+
+func (app *App) has(svc service) bool {
+	return len(app.services[svc]) > 0
+}
+
+func (app *App) logger(ctx *BuilderContext) Logger {
+	return ctx.logger().With(LogService, From[App]())
+}
+
+func (app *App) Inspect() {
+	fmt.Println("Services:")
+	for _, module := range append([]string{""}, app.modules...) {
+		if module == "" {
+			fmt.Println(".   app:")
+		} else {
+			fmt.Printf(".   %v:\n", module)
+		}
+		for _, reg := range app.registrations {
+			switch {
+			case reg.module != module:
+			case reg.declared:
+				fmt.Printf("    .   %v %v\n", reg, reg.lifetime)
+			default:
+				fmt.Printf("    .   %v\n", reg)
+			}
+		}
+	}
 	fmt.Println()
 	fmt.Println("Config:")
 	fmt.Printf(".   %v\n", app.config)
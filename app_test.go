@@ -0,0 +1,382 @@
+package gofast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type validationMissing interface {
+	Missing()
+}
+
+type validationService struct{}
+
+func TestApp_Validate(t *testing.T) {
+	app := newQuietApp(t)
+
+	if err := app.Validate(); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+}
+
+func TestApp_ValidateAggregatesErrors(t *testing.T) {
+	app := Empty(&AppConfig{Name: "test"})
+	Register[*validationService](app, func(ctx *BuilderContext) *validationService {
+		MustGet[validationMissing](ctx, Scoped)
+		return &validationService{}
+	})
+	Register[any](app, func(ctx *BuilderContext) *validationService {
+		return nil
+	})
+	Register[string](app, func(ctx *BuilderContext) string {
+		panic("boom")
+	})
+
+	err := app.Validate()
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+
+	for _, want := range []string{
+		"gofast.UniqueIDGenerator: not registered",
+		"resolve gofast.validationMissing in gofast-scope-request-validation: service not found",
+		"builder returned nil",
+		"boom",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to contain %q, got: %v", want, err)
+		}
+	}
+}
+
+type scopeService struct{ closed bool }
+
+func (s *scopeService) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestApp_NewScope(t *testing.T) {
+	app := newQuietApp(t)
+	Register[*scopeService](app, func(ctx *BuilderContext) *scopeService {
+		return &scopeService{}
+	})
+
+	ctx1, release1 := app.NewScope(context.Background())
+	ctx2, release2 := app.NewScope(context.Background())
+	defer release2()
+
+	if ctx1.RequestID() == ctx2.RequestID() {
+		t.Fatalf("Expected unique scope IDs, got %s twice", ctx1.RequestID())
+	}
+
+	s1 := MustGet[*scopeService](ctx1, Scoped)
+	if s1 != MustGet[*scopeService](ctx1, Scoped) {
+		t.Error("Expected the same instance within a scope")
+	}
+	if s1 == MustGet[*scopeService](ctx2, Scoped) {
+		t.Error("Expected different instances across scopes")
+	}
+
+	release1()
+	release1()
+	if !s1.closed {
+		t.Error("Expected scoped instance to be disposed on release")
+	}
+}
+
+func TestNew_Options(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.json")
+	second := filepath.Join(dir, "second.json")
+	for file, name := range map[string]string{first: "first", second: "second"} {
+		if err := os.WriteFile(file, []byte(`{"Name":"`+name+`"}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a, cfg := New(WithConfigFiles(first), WithEnv(false), WithDebug(true))
+	b, _ := New(WithConfigFiles(second), WithEnv(false), WithEnvironment("staging"))
+
+	if cfg.Name != "first" || b.config.Name != "second" {
+		t.Errorf("Expected per-app config files, got %q and %q", cfg.Name, b.config.Name)
+	}
+	if !newTestContext(a).Settings().DEBUG || newTestContext(b).Settings().DEBUG {
+		t.Error("Expected debug flag to be per-app")
+	}
+	if env := newTestContext(b).Environment(); env != "staging" {
+		t.Errorf("Expected environment %q, got %q", "staging", env)
+	}
+	if len(ConfigFiles.files) != 0 {
+		t.Errorf("Expected global config files to be untouched, got %v", ConfigFiles.files)
+	}
+}
+
+type standardConfig struct {
+	File string
+	Env  string
+}
+
+func TestNewConfig_StandardSources(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("config.json", []byte(`{"Standard":{"File":"file","Env":"file"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOFAST_Standard__Env", "env")
+
+	newQuietApp(t)
+	cfg := NewConfig(standardConfig{}, "Standard").Value()
+
+	if cfg.File != "file" || cfg.Env != "env" {
+		t.Errorf("Expected config file and environment to be read, got %+v", cfg)
+	}
+}
+
+type runEvents struct {
+	mu   sync.Mutex
+	list []string
+}
+
+func (e *runEvents) add(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.list = append(e.list, event)
+}
+
+func (e *runEvents) String() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return strings.Join(e.list, ", ")
+}
+
+func (e *runEvents) hook(event string, err error) Hook {
+	return func(*BuilderContext) error {
+		e.add(event)
+		return err
+	}
+}
+
+type runService struct {
+	name     string
+	events   *runEvents
+	err      error
+	critical bool
+}
+
+func (s *runService) Start(ctx context.Context) error {
+	s.events.add("start " + s.name)
+	return s.err
+}
+
+func (s *runService) Stop(ctx context.Context) error {
+	s.events.add("stop " + s.name)
+	return nil
+}
+
+func (s *runService) Critical() bool {
+	return s.critical
+}
+
+func host(app *App, s *runService) {
+	Host(app, func(*BuilderContext) *runService { return s })
+}
+
+func newRunApp(t *testing.T, opts ...Option) *App {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener.Close()
+	app := newQuietApp(t, opts...)
+	app.config.Server.Host = "127.0.0.1"
+	app.config.Server.Port = listener.Addr().(*net.TCPAddr).Port
+	return app
+}
+
+func TestRun_Lifecycle(t *testing.T) {
+	events := &runEvents{}
+	app := newRunApp(t)
+	ctx, cancel := context.WithCancel(t.Context())
+	host(app, &runService{name: "a", events: events})
+	host(app, &runService{name: "b", events: events})
+	app.OnStarting(events.hook("starting", nil))
+	app.OnStarted(func(ctx *BuilderContext) error {
+		events.add("started")
+		cancel()
+		return nil
+	})
+	app.OnStopping(events.hook("stopping", nil))
+	app.OnStopped(events.hook("stopped", nil))
+
+	if err := app.Run(ctx); err != nil {
+		t.Fatalf("Unexpected run error: %v", err)
+	}
+
+	want := "starting, start a, start b, started, stopping, stop b, stop a, stopped"
+	if events.String() != want {
+		t.Errorf("Expected %q, got %q", want, events.String())
+	}
+}
+
+func TestRun_CriticalServiceAbort(t *testing.T) {
+	events := &runEvents{}
+	app := newRunApp(t)
+	host(app, &runService{name: "a", events: events})
+	host(app, &runService{name: "optional", events: events, err: errors.New("unavailable")})
+	host(app, &runService{name: "critical", events: events, err: errors.New("unavailable"), critical: true})
+	host(app, &runService{name: "c", events: events})
+	app.OnStarted(events.hook("started", nil))
+
+	err := app.Run(t.Context())
+	if err == nil || !strings.Contains(err.Error(), "hosted service start failed: unavailable") {
+		t.Fatalf("Expected hosted service start error, got: %v", err)
+	}
+
+	want := "start a, start optional, start critical, stop a"
+	if events.String() != want {
+		t.Errorf("Expected %q, got %q", want, events.String())
+	}
+}
+
+func TestRun_HookFailure(t *testing.T) {
+	events := &runEvents{}
+	app := newRunApp(t)
+	host(app, &runService{name: "a", events: events})
+	app.OnStarting(events.hook("starting 1", nil))
+	app.OnStarting(events.hook("starting 2", errors.New("migration failed")))
+	app.OnStarting(events.hook("starting 3", nil))
+
+	err := app.Run(t.Context())
+	if err == nil || !strings.Contains(err.Error(), "starting hook failed: migration failed") {
+		t.Fatalf("Expected starting hook error, got: %v", err)
+	}
+	if want := "starting 1, starting 2"; events.String() != want {
+		t.Errorf("Expected %q, got %q", want, events.String())
+	}
+
+	events = &runEvents{}
+	app = newRunApp(t)
+	ctx, cancel := context.WithCancel(t.Context())
+	app.OnStarted(func(*BuilderContext) error {
+		cancel()
+		return nil
+	})
+	app.OnStopping(events.hook("stopping 1", errors.New("flush failed")))
+	app.OnStopping(events.hook("stopping 2", nil))
+	app.OnStopped(events.hook("stopped", nil))
+
+	err = app.Run(ctx)
+	if err == nil || !strings.Contains(err.Error(), "stopping hook failed: flush failed") {
+		t.Fatalf("Expected stopping hook error, got: %v", err)
+	}
+	if want := "stopping 1, stopping 2, stopped"; events.String() != want {
+		t.Errorf("Expected %q, got %q", want, events.String())
+	}
+}
+
+func TestRun_PortInUse(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	app := newRunApp(t)
+	app.config.Server.Port = listener.Addr().(*net.TCPAddr).Port
+
+	err = app.Run(t.Context())
+	if err == nil || !strings.Contains(err.Error(), "server listen failed") {
+		t.Fatalf("Expected listen error, got: %v", err)
+	}
+}
+
+type runController struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (c *runController) Prefix() string {
+	return "run"
+}
+
+func (c *runController) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /slow", func(w http.ResponseWriter, r *http.Request) {
+		close(c.entered)
+		<-c.release
+	})
+	return mux
+}
+
+func TestRun_ShutdownTimeout(t *testing.T) {
+	ctrl := &runController{entered: make(chan struct{}), release: make(chan struct{})}
+	defer close(ctrl.release)
+	app := newRunApp(t, WithShutdownTimeout(10*time.Millisecond))
+	RemoveImpl[Middleware, *TimeoutMiddleware](app)
+	Add(app, func(*BuilderContext) *runController { return ctrl }, AsSingleton)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	app.OnStarted(func(*BuilderContext) error {
+		go http.Get(fmt.Sprintf("http://127.0.0.1:%d/run/slow", app.config.Server.Port))
+		<-ctrl.entered
+		cancel()
+		return nil
+	})
+
+	err := app.Run(ctx)
+	if err == nil || !strings.Contains(err.Error(), "server shutdown failed: context deadline exceeded") {
+		t.Fatalf("Expected shutdown timeout error, got: %v", err)
+	}
+}
+
+type runWorker struct {
+	*runService
+	failures chan error
+}
+
+func (w *runWorker) Failed() <-chan error {
+	return w.failures
+}
+
+func TestRun_ServiceFailure(t *testing.T) {
+	events := &runEvents{}
+	worker := &runWorker{runService: &runService{name: "worker", events: events, critical: true}, failures: make(chan error, 1)}
+	app := newRunApp(t)
+	Host(app, func(*BuilderContext) *runWorker { return worker })
+	app.OnStarted(func(*BuilderContext) error {
+		worker.failures <- errors.New("consumer died")
+		return nil
+	})
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	err := app.Run(ctx)
+	if err == nil || !strings.Contains(err.Error(), "hosted service failed: consumer died") {
+		t.Fatalf("Expected hosted service failure, got: %v", err)
+	}
+	if want := "start worker, stop worker"; events.String() != want {
+		t.Errorf("Expected %q, got %q", want, events.String())
+	}
+
+	worker = &runWorker{runService: &runService{name: "worker", events: &runEvents{}}, failures: make(chan error, 1)}
+	app = newRunApp(t)
+	Host(app, func(*BuilderContext) *runWorker { return worker })
+	ctx, cancel = context.WithCancel(t.Context())
+	app.OnStarted(func(*BuilderContext) error {
+		worker.failures <- errors.New("poll failed")
+		time.AfterFunc(20*time.Millisecond, cancel)
+		return nil
+	})
+
+	if err := app.Run(ctx); err != nil {
+		t.Errorf("Expected non-critical failure to keep running, got: %v", err)
+	}
+}
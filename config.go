@@ -3,6 +3,7 @@ package gofast
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"strings"
 )
@@ -24,29 +25,25 @@ type FastConfig[T any] struct {
 }
 
 func ConfigBuilder[T any](v T, keys ...string) Builder[*FastConfig[T]] {
-	return func(*BuilderContext) *FastConfig[T] {
-		return NewConfig(v, keys...)
+	return func(ctx *BuilderContext) *FastConfig[T] {
+		return new_config(ctx.options(), validator_of(ctx), v, keys...)
 	}
 }
 
 func NewConfig[T any](v T, keys ...string) *FastConfig[T] {
+	return new_config(new_options(true, nil), defaultValidator, v, keys...)
+}
+
+func new_config[T any](o *options, validator *Validator, v T, keys ...string) *FastConfig[T] {
 	if len(keys) == 0 {
 		if resolver, ok := any(v).(ConfigResolver); ok {
 			keys = resolver.Path()
 		}
 	}
-	for _, file := range ConfigFiles.files {
-		data, err := os.ReadFile(file)
-		if err == nil {
-			_ = get_nested_config(data, &v, keys...)
-		}
-	}
-	if ConfigFiles.env {
-		data, err := read_env(CONFIG.ENV_PREFIX)
-		if err == nil {
-			_ = get_nested_config(data, &v, keys...)
-		}
+	if err := o.read(&v, keys...); err != nil {
+		panic(fmt.Sprintf("invalid config %T: %v", v, err))
 	}
+	validate_config(validator, v)
 	return &FastConfig[T]{value: v}
 }
 
@@ -54,6 +51,8 @@ func (c *FastConfig[T]) Value() T {
 	return c.value
 }
 
+var errConfigKeyNotFound = errors.New("config key not found")
+
 func get_nested_config[T any](data []byte, v *T, keys ...string) error {
 	root := map[string]any{}
 	if err := json.Unmarshal(data, &root); err != nil {
@@ -65,7 +64,7 @@ func get_nested_config[T any](data []byte, v *T, keys ...string) error {
 	for _, key := range keys {
 		value, ok := current[key]
 		if !ok {
-			return errors.New("config key not found: " + key)
+			return fmt.Errorf("%w: %s", errConfigKeyNotFound, key)
 		}
 		current, ok = value.(map[string]any)
 		if !ok {
@@ -2,6 +2,9 @@ package gofast
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"slices"
 
 	"github.com/ugozlave/cargo"
 )
@@ -11,18 +14,43 @@ type ContextKey string
 const (
 	CtxName      ContextKey = "Name"
 	CtxRequestId ContextKey = "RequestId"
+	CtxApp       ContextKey = "App"
+	CtxPath      ContextKey = "Path"
 )
 
 type BuilderContext struct {
 	context.Context
 	container *cargo.Container
+	app       *App
+	stack     []resolution
+}
+
+type builderKey struct{}
+
+type resolution struct {
+	service  service
+	lifetime Lifetime
 }
 
 func NewBuilderContext(ctx context.Context, container *cargo.Container) *BuilderContext {
+	app, _ := ctx.Value(CtxApp).(*App)
 	return &BuilderContext{
 		Context:   ctx,
 		container: container,
+		app:       app,
+	}
+}
+
+func FromContext(ctx context.Context) *BuilderContext {
+	c, _ := ctx.Value(builderKey{}).(*BuilderContext)
+	return c
+}
+
+func (c *BuilderContext) Value(key any) any {
+	if key == (builderKey{}) {
+		return c
 	}
+	return c.Context.Value(key)
 }
 
 func (c *BuilderContext) Name() string {
@@ -40,3 +68,97 @@ func (c *BuilderContext) RequestID() string {
 	}
 	return v
 }
+
+func (c *BuilderContext) Settings() Settings {
+	return c.options().settings
+}
+
+func (c *BuilderContext) Environment() string {
+	return c.options().environment.value
+}
+
+func (c *BuilderContext) options() *options {
+	if c.app == nil {
+		return default_options()
+	}
+	return c.app.options
+}
+
+func (c *BuilderContext) enter(svc service, lt Lifetime) *BuilderContext {
+	for i, r := range c.stack {
+		if r.service == svc {
+			panic(&CycleError{Path: c.path(i, svc)})
+		}
+	}
+	for _, r := range slices.Backward(c.stack) {
+		if r.lifetime == Transient {
+			continue
+		}
+		if lt != Transient && lt > r.lifetime {
+			err := &CaptiveError{Path: c.path(0, svc), Lifetime: lt, Owner: r.service.String(), OwnerLifetime: r.lifetime}
+			if c.Settings().DEBUG {
+				panic(err)
+			}
+			c.logger().Wrn(err.Error())
+		}
+		break
+	}
+	child := *c
+	child.stack = append(slices.Clip(c.stack), resolution{service: svc, lifetime: lt})
+	return &child
+}
+
+func (c *BuilderContext) scope(lt Lifetime) string {
+	switch lt {
+	case Singleton:
+		return fmt.Sprintf(ScopeApplicationKeyFormat, c.Name())
+	case Scoped:
+		return fmt.Sprintf(ScopeRequestKeyFormat, c.RequestID())
+	default:
+		return ""
+	}
+}
+
+func (c *BuilderContext) lifetime() Lifetime {
+	if len(c.stack) == 0 {
+		return Transient
+	}
+	return c.stack[len(c.stack)-1].lifetime
+}
+
+func (c *BuilderContext) detach(svc service) *BuilderContext {
+	owner := Transient
+	for _, r := range slices.Backward(c.stack) {
+		if r.lifetime != Transient {
+			owner = r.lifetime
+			break
+		}
+	}
+	child := *c
+	child.stack = []resolution{{service: svc, lifetime: owner}}
+	return &child
+}
+
+func (c *BuilderContext) path(from int, svc service) []string {
+	path := make([]string, 0, len(c.stack)-from+1)
+	for _, r := range c.stack[from:] {
+		path = append(path, r.service.String())
+	}
+	return append(path, svc.String())
+}
+
+func (c *BuilderContext) logger() Logger {
+	svc := service{key: From[Logger]()}
+	if c.app == nil || !c.app.has(svc) {
+		return &FastLogger{logger: slog.Default()}
+	}
+	for _, r := range c.stack {
+		if r.service == svc {
+			return &FastLogger{logger: slog.Default()}
+		}
+	}
+	if logger, ok := resolve(c, svc, nil).(Logger); ok {
+		return logger
+	}
+	return &FastLogger{logger: slog.Default()}
+}
@@ -3,6 +3,8 @@ package gofast
 import (
 	"encoding/json"
 	"net/http"
+	"slices"
+	"sync"
 )
 
 type Controller interface {
@@ -10,20 +12,30 @@ type Controller interface {
 	Routes() http.Handler
 }
 
+type MiddlewareController interface {
+	ControllerMiddlewares() []Middleware
+}
+
+func controller_routes(ctrl Controller, renderer ErrorRenderer) http.Handler {
+	routes := render_unmatched(ctrl.Routes(), renderer)
+	if mc, ok := ctrl.(MiddlewareController); ok {
+		for _, mid := range slices.Backward(mc.ControllerMiddlewares()) {
+			routes = mid.Handle(routes)
+		}
+	}
+	return routes
+}
+
 /*
 ** HealthController
  */
 
 type HealthController struct {
-	Services []HealthChecker
+	Services []HealthChecker `inject:""`
 }
 
 func HealthControllerBuilder() Builder[*HealthController] {
-	return func(ctx *BuilderContext) *HealthController {
-		return &HealthController{
-			Services: All[HealthChecker](ctx, Scoped),
-		}
-	}
+	return StructBuilder[*HealthController]()
 }
 
 func (c *HealthController) Prefix() string {
@@ -31,9 +43,12 @@ func (c *HealthController) Prefix() string {
 }
 
 func (c *HealthController) Routes() http.Handler {
-	mux := http.NewServeMux()
-	mux.HandleFunc("GET /{$}", c.handle)
-	return mux
+	router := NewRouter()
+	route := router.HandleFunc("GET /{$}", c.handle)
+	route.Summary = "Health status"
+	route.Response = From[map[string]bool]()
+	route.Errors = []int{http.StatusServiceUnavailable}
+	return router
 }
 
 func (c *HealthController) handle(w http.ResponseWriter, r *http.Request) {
@@ -50,3 +65,55 @@ func (c *HealthController) handle(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(code)
 	json.NewEncoder(w).Encode(status)
 }
+
+/*
+** OpenAPIController
+ */
+
+type OpenAPIConfig struct {
+	Prefix  string `json:"Prefix"`
+	Title   string `json:"Title"`
+	Version string `json:"Version"`
+}
+
+func (OpenAPIConfig) Path() []string {
+	return []string{"OpenAPI"}
+}
+
+type OpenAPIController struct {
+	config OpenAPIConfig
+}
+
+func OpenAPIControllerBuilder() Builder[*OpenAPIController] {
+	var once sync.Once
+	var defaults func() OpenAPIConfig
+	return func(ctx *BuilderContext) *OpenAPIController {
+		if config, err := TryGet[Config[OpenAPIConfig]](ctx); err == nil {
+			return &OpenAPIController{config: config.Value()}
+		}
+		// the config sources are read on the first build only, not on every request
+		once.Do(func() {
+			defaults = sync.OnceValue(func() OpenAPIConfig {
+				cfg := OpenAPIConfig{Prefix: "openapi", Title: ctx.Name(), Version: "1.0.0"}
+				return new_config(ctx.options(), validator_of(ctx), cfg).Value()
+			})
+		})
+		return &OpenAPIController{config: defaults()}
+	}
+}
+
+func (c *OpenAPIController) Prefix() string {
+	return c.config.Prefix
+}
+
+func (c *OpenAPIController) Routes() http.Handler {
+	router := NewRouter()
+	router.HandleFunc("GET /{$}", c.handle).Summary = "OpenAPI document"
+	return router
+}
+
+func (c *OpenAPIController) handle(w http.ResponseWriter, r *http.Request) {
+	ctx := request_context(r)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(new_openapi(All[Controller](ctx), c.config))
+}
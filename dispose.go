@@ -0,0 +1,98 @@
+package gofast
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/ugozlave/cargo"
+)
+
+type Disposable interface {
+	Dispose(ctx context.Context) error
+}
+
+const disposerKey = "gofast-disposer"
+
+/*
+** disposer
+ */
+
+type disposer struct {
+	ctx       *BuilderContext
+	logger    Logger
+	instances []any
+	mu        sync.Mutex
+}
+
+func disposer_builder(ctn *cargo.Container) cargo.Builder[any] {
+	return func(ctx cargo.BuilderContext) any {
+		return &disposer{ctx: NewBuilderContext(context.WithoutCancel(ctx), ctn)}
+	}
+}
+
+func (d *disposer) add(instance any, logger func() Logger) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// captured while the scopes are alive, so Close never resolves
+	if d.logger == nil {
+		d.logger = logger()
+	}
+	d.instances = append(d.instances, instance)
+}
+
+func (d *disposer) Close() {
+	d.mu.Lock()
+	instances := d.instances
+	logger := d.logger
+	d.instances = nil
+	d.mu.Unlock()
+	for _, instance := range slices.Backward(instances) {
+		if err := dispose(d.ctx, instance); err != nil {
+			logger.Err("dispose failed", LogService, reflect.TypeOf(instance), LogError, err)
+		}
+	}
+}
+
+func dispose(ctx context.Context, instance any) error {
+	switch v := instance.(type) {
+	case Disposable:
+		return v.Dispose(ctx)
+	case io.Closer:
+		return v.Close()
+	}
+	return nil
+}
+
+func create_scope(ctx *BuilderContext, scope string) {
+	ctx.container.CreateScope(scope)
+	ctx.container.MustGet(disposerKey, scope, ctx)
+	if ctx.app != nil {
+		ctx.app.scopes.Store(scope, true)
+	}
+}
+
+func delete_scope(ctx *BuilderContext, scope string) {
+	if ctx.app != nil {
+		ctx.app.scopes.Delete(scope)
+	}
+	ctx.container.DeleteScope(scope)
+}
+
+func (c *BuilderContext) track(instance any) {
+	switch instance.(type) {
+	case Disposable, io.Closer:
+	default:
+		return
+	}
+	if len(c.stack) == 0 {
+		return
+	}
+	scope := c.scope(c.stack[len(c.stack)-1].lifetime)
+	if scope == "" {
+		return
+	}
+	c.container.MustGet(disposerKey, scope, c).(*disposer).add(instance, c.logger)
+}
@@ -13,7 +13,11 @@ type EnvironmentHelper struct {
 }
 
 func (e *EnvironmentHelper) Read() {
-	value, ok := os.LookupEnv(CONFIG.ENV_PREFIX + "_ENVIRONMENT")
+	e.read(CONFIG.ENV_PREFIX)
+}
+
+func (e *EnvironmentHelper) read(prefix string) {
+	value, ok := os.LookupEnv(prefix + "_ENVIRONMENT")
 	if !ok {
 		value = "production"
 	}
@@ -0,0 +1,73 @@
+package gofast
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var ErrNotFound = errors.New("service not found")
+
+type ResolveError struct {
+	Type  reflect.Type
+	Name  string
+	Scope string
+	Err   error
+}
+
+func (e *ResolveError) Error() string {
+	svc := service{key: e.Type, name: e.Name}
+	if e.Scope == "" {
+		return fmt.Sprintf("resolve %v: %v", svc, e.Err)
+	}
+	return fmt.Sprintf("resolve %v in %s: %v", svc, e.Scope, e.Err)
+}
+
+func (e *ResolveError) Unwrap() error {
+	return e.Err
+}
+
+func as_error(v any) error {
+	if err, ok := v.(error); ok {
+		return err
+	}
+	return errors.New(fmt.Sprint(v))
+}
+
+type CycleError struct {
+	Path []string
+}
+
+func (e *CycleError) Error() string {
+	return "circular dependency: " + strings.Join(e.Path, " -> ")
+}
+
+type CaptiveError struct {
+	Path          []string
+	Lifetime      Lifetime
+	Owner         string
+	OwnerLifetime Lifetime
+}
+
+func (e *CaptiveError) Error() string {
+	key := e.Path[len(e.Path)-1]
+	return fmt.Sprintf("captive dependency: %v %v resolved into %v %v: %s", e.Lifetime, key, e.OwnerLifetime, e.Owner, strings.Join(e.Path, " -> "))
+}
+
+type BindError struct {
+	Source string
+	Field  string
+	Err    error
+}
+
+func (e *BindError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("bind %s: %v", e.Source, e.Err)
+	}
+	return fmt.Sprintf("bind %s %q: %v", e.Source, e.Field, e.Err)
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
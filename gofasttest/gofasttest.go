@@ -0,0 +1,77 @@
+package gofasttest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ugozlave/gofast"
+)
+
+type App struct {
+	*gofast.App
+	tb      testing.TB
+	handler http.Handler
+}
+
+func New(tb testing.TB, opts ...gofast.Option) *App {
+	tb.Helper()
+	app, _ := gofast.New(isolate(opts)...)
+	gofast.Replace[gofast.Config[gofast.LoggerConfig]](app, gofast.ConfigBuilder(gofast.LoggerConfig{Level: "info", Discard: true}), gofast.AsSingleton)
+	return wrap(tb, app)
+}
+
+func Empty(tb testing.TB, cfg *gofast.AppConfig, opts ...gofast.Option) *App {
+	tb.Helper()
+	return wrap(tb, gofast.Empty(cfg, isolate(opts)...))
+}
+
+func Override[K any, V any](app *App, builder func(*gofast.BuilderContext) V, opts ...gofast.RegisterOption) {
+	OverrideKeyed[K](app, "", builder, opts...)
+}
+
+func OverrideKeyed[K any, V any](app *App, name string, builder func(*gofast.BuilderContext) V, opts ...gofast.RegisterOption) {
+	app.tb.Helper()
+	if app.handler != nil {
+		app.tb.Fatalf("gofasttest: cannot override %v after the handler was built", gofast.From[K]())
+	}
+	gofast.ReplaceKeyed[K](app.App, name, builder, opts...)
+}
+
+func (app *App) Handler() http.Handler {
+	app.tb.Helper()
+	if app.handler == nil {
+		if err := app.Validate(); err != nil {
+			app.tb.Fatalf("gofasttest: validation failed: %v", err)
+		}
+		app.handler = app.App.Handler()
+	}
+	return app.handler
+}
+
+func (app *App) Do(r *http.Request) *httptest.ResponseRecorder {
+	app.tb.Helper()
+	w := httptest.NewRecorder()
+	app.Handler().ServeHTTP(w, r)
+	return w
+}
+
+func (app *App) Scope() *gofast.BuilderContext {
+	app.tb.Helper()
+	ctx, release := app.NewScope(app.tb.Context())
+	app.tb.Cleanup(release)
+	return ctx
+}
+
+func wrap(tb testing.TB, app *gofast.App) *App {
+	tb.Cleanup(app.Close)
+	return &App{App: app, tb: tb}
+}
+
+func isolate(opts []gofast.Option) []gofast.Option {
+	return append([]gofast.Option{
+		gofast.WithConfigFiles(),
+		gofast.WithEnv(false),
+		gofast.WithEnvironment("test"),
+	}, opts...)
+}
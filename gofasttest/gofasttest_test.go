@@ -0,0 +1,71 @@
+package gofasttest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ugozlave/gofast"
+)
+
+type fakeChecker struct{}
+
+func (fakeChecker) HealthCheck() (string, bool, error) {
+	return "fake", false, errors.New("down")
+}
+
+type fakeCache struct{ gofast.Cache }
+
+func (*fakeCache) Close() {}
+
+func TestApp_Handler(t *testing.T) {
+	app := New(t)
+
+	w := app.Do(httptest.NewRequest(http.MethodGet, "/health/", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestApp_Override(t *testing.T) {
+	app := New(t)
+	Override[gofast.HealthChecker](app, func(*gofast.BuilderContext) fakeChecker {
+		return fakeChecker{}
+	})
+	Override[gofast.Cache](app, func(*gofast.BuilderContext) *fakeCache {
+		return &fakeCache{}
+	}, gofast.AsSingleton)
+
+	w := app.Do(httptest.NewRequest(http.MethodGet, "/health/", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+
+	ctx := app.Scope()
+	if _, ok := gofast.MustGet[gofast.Cache](ctx).(*fakeCache); !ok {
+		t.Fatal("Expected overridden cache")
+	}
+	if ctx.RequestID() == "" {
+		t.Fatal("Expected request-scoped context")
+	}
+}
+
+func TestApp_ConfigIsolated(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(file, []byte(`{"Name":"isolated"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+	t.Setenv(gofast.CONFIG.ENV_PREFIX+"_Name", "environment")
+
+	if name := New(t).Scope().Name(); name != "gofast" {
+		t.Errorf("Expected name %q, got %q", "gofast", name)
+	}
+	if name := New(t, gofast.WithConfigFiles(file)).Scope().Name(); name != "isolated" {
+		t.Errorf("Expected name %q, got %q", "isolated", name)
+	}
+}
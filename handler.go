@@ -0,0 +1,262 @@
+package gofast
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+const (
+	BindPath   string = "path"
+	BindQuery  string = "query"
+	BindHeader string = "header"
+	BindForm   string = "form"
+	BindBody   string = "body"
+)
+
+type StatusCoder interface {
+	StatusCode() int
+}
+
+func Handle[Req any, Resp any](handler func(ctx *BuilderContext, req Req) (Resp, error)) http.Handler {
+	if handler == nil {
+		panic("handler function cannot be nil")
+	}
+	b := new_binder(From[Req]())
+	return &typedHandler{request: From[Req](), response: From[Resp](), HandlerFunc: func(w http.ResponseWriter, r *http.Request) {
+		ctx := request_context(r)
+		var req Req
+		if err := b.bind(r, reflect.ValueOf(&req).Elem()); err != nil {
+			write_error(ctx, w, r, err)
+			return
+		}
+		if err := validator_of(ctx).Validate(req); err != nil {
+			write_error(ctx, w, r, err)
+			return
+		}
+		resp, err := handler(ctx, req)
+		if err != nil {
+			write_error(ctx, w, r, err)
+			return
+		}
+		write_json(w, resp)
+	}}
+}
+
+type typedHandler struct {
+	http.HandlerFunc
+	request  reflect.Type
+	response reflect.Type
+}
+
+func request_context(r *http.Request) *BuilderContext {
+	parent := FromContext(r.Context())
+	if parent == nil {
+		return NewBuilderContext(r.Context(), nil)
+	}
+	child := *parent
+	child.Context = r.Context()
+	return &child
+}
+
+func write_json(w http.ResponseWriter, v any) {
+	// nil slices and maps are still encoded, only a missing value has no content
+	if value := reflect.ValueOf(v); v == nil || value.Kind() == reflect.Pointer && value.IsNil() {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	code := http.StatusOK
+	if coder, ok := v.(StatusCoder); ok {
+		code = coder.StatusCode()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func write_error(ctx *BuilderContext, w http.ResponseWriter, r *http.Request, err error) {
+	he := AsHTTPError(err)
+	if he.Status >= http.StatusInternalServerError {
+		ctx.logger().Err("handler failed", LogRequestId, ctx.RequestID(), LogError, err)
+	}
+	renderer_of(ctx).Render(w, r, he)
+}
+
+/*
+** Binder
+ */
+
+type binder struct {
+	pointer bool
+	fields  []binding
+}
+
+type binding struct {
+	index  []int
+	source string
+	name   string
+}
+
+func new_binder(t reflect.Type) *binder {
+	b := &binder{}
+	if t.Kind() == reflect.Pointer {
+		b.pointer = true
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		b.collect(t, nil)
+	}
+	return b
+}
+
+func (b *binder) collect(t reflect.Type, index []int) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		idx := append(index[:len(index):len(index)], i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			b.collect(f.Type, idx)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		for _, source := range []string{BindPath, BindQuery, BindHeader, BindForm} {
+			if name, ok := f.Tag.Lookup(source); ok && name != "-" {
+				b.fields = append(b.fields, binding{index: idx, source: source, name: name})
+			}
+		}
+	}
+}
+
+func (b *binder) bind(r *http.Request, v reflect.Value) error {
+	if b.pointer {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	form, err := b.body(r, v)
+	if err != nil {
+		return err
+	}
+	var query url.Values
+	for _, f := range b.fields {
+		var values []string
+		switch f.source {
+		case BindPath:
+			if value := r.PathValue(f.name); value != "" {
+				values = []string{value}
+			}
+		case BindQuery:
+			if query == nil {
+				query = r.URL.Query()
+			}
+			values = query[f.name]
+		case BindHeader:
+			values = r.Header.Values(f.name)
+		case BindForm:
+			values = form[f.name]
+		}
+		if len(values) == 0 {
+			continue
+		}
+		if err := set_value(v.FieldByIndex(f.index), values); err != nil {
+			return &BindError{Source: f.source, Field: f.name, Err: err}
+		}
+	}
+	return nil
+}
+
+func (b *binder) body(r *http.Request, v reflect.Value) (url.Values, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	media, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch media {
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return nil, &BindError{Source: BindForm, Err: err}
+		}
+		return r.PostForm, nil
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return nil, &BindError{Source: BindForm, Err: err}
+		}
+		return r.MultipartForm.Value, nil
+	}
+	if err := json.NewDecoder(r.Body).Decode(v.Addr().Interface()); err != nil && err != io.EOF {
+		return nil, &BindError{Source: BindBody, Err: err}
+	}
+	// tagged fields only come from their own source, never from the body
+	for _, f := range b.fields {
+		v.FieldByIndex(f.index).SetZero()
+	}
+	return nil, nil
+}
+
+var durationType = From[time.Duration]()
+
+func set_value(v reflect.Value, values []string) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return set_value(v.Elem(), values)
+	}
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(values[0]))
+	}
+	value := values[0]
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Slice:
+		slice := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := set_value(slice.Index(i), []string{value}); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return errors.New("unsupported type " + v.Type().String())
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package gofast
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type handleRequest struct {
+	ID      int           `path:"id"`
+	Tags    []string      `query:"tag"`
+	Wait    time.Duration `query:"wait"`
+	Token   string        `header:"X-Token"`
+	Name    string        `json:"name"`
+	Enabled *bool         `form:"enabled"`
+}
+
+type handleResponse struct {
+	Message string `json:"message"`
+}
+
+func (handleResponse) StatusCode() int {
+	return http.StatusCreated
+}
+
+type handleError struct{}
+
+func (handleError) Error() string {
+	return "item is locked"
+}
+
+func (handleError) StatusCode() int {
+	return http.StatusConflict
+}
+
+func newHandleMux(got *handleRequest) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("POST /items/{id}", Handle(func(ctx *BuilderContext, req handleRequest) (*handleResponse, error) {
+		*got = req
+		switch req.Name {
+		case "locked":
+			return nil, handleError{}
+		case "broken":
+			return nil, errors.New("database down")
+		case "empty":
+			return nil, nil
+		}
+		return &handleResponse{Message: "ok"}, nil
+	}))
+	return mux
+}
+
+func TestHandle_Bind(t *testing.T) {
+	var got handleRequest
+	mux := newHandleMux(&got)
+
+	r := httptest.NewRequest(http.MethodPost, "/items/7?tag=a&tag=b&wait=2s", strings.NewReader(`{"name":"gopher"}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-Token", "secret")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+
+	if w.Code != http.StatusCreated || strings.TrimSpace(w.Body.String()) != `{"message":"ok"}` {
+		t.Fatalf("Unexpected response %d %q", w.Code, w.Body.String())
+	}
+	if got.ID != 7 || len(got.Tags) != 2 || got.Wait != 2*time.Second || got.Token != "secret" || got.Name != "gopher" {
+		t.Errorf("Unexpected binding: %+v", got)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/items/7", strings.NewReader("name=ignored&enabled=true"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	mux.ServeHTTP(httptest.NewRecorder(), r)
+	if got.Enabled == nil || !*got.Enabled {
+		t.Errorf("Expected form value to be bound, got %+v", got)
+	}
+}
+
+func TestHandle_Status(t *testing.T) {
+	var got handleRequest
+	mux := newHandleMux(&got)
+
+	for _, tc := range []struct {
+		path string
+		body string
+		code int
+	}{
+		{"/items/x", `{}`, http.StatusBadRequest},
+		{"/items/1", `{"name":`, http.StatusBadRequest},
+		{"/items/1", `{"name":"locked"}`, http.StatusConflict},
+		{"/items/1", `{"name":"broken"}`, http.StatusInternalServerError},
+		{"/items/1", `{"name":"empty"}`, http.StatusNoContent},
+	} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body)))
+		if w.Code != tc.code {
+			t.Errorf("%s %s: expected status %d, got %d", tc.path, tc.body, tc.code, w.Code)
+		}
+	}
+}
+
+func TestHandle_BodyCannotSpoofBoundFields(t *testing.T) {
+	var got handleRequest
+	mux := newHandleMux(&got)
+
+	r := httptest.NewRequest(http.MethodPost, "/items/7", strings.NewReader(`{"ID":99,"Token":"admin","Tags":["x"],"Enabled":true,"name":"gopher"}`))
+	r.Header.Set("Content-Type", "application/json")
+	mux.ServeHTTP(httptest.NewRecorder(), r)
+
+	if got.ID != 7 || got.Token != "" || got.Tags != nil || got.Enabled != nil || got.Name != "gopher" {
+		t.Errorf("Expected bound fields to ignore the body, got %+v", got)
+	}
+}
+
+func TestHandle_NilCollections(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /items", Handle(func(ctx *BuilderContext, _ struct{}) ([]string, error) {
+		return nil, nil
+	}))
+	mux.Handle("GET /tags", Handle(func(ctx *BuilderContext, _ struct{}) (map[string]int, error) {
+		return nil, nil
+	}))
+	mux.Handle("GET /none", Handle(func(ctx *BuilderContext, _ struct{}) (any, error) {
+		return nil, nil
+	}))
+
+	for path, want := range map[string]string{"/items": "null", "/tags": "null", "/none": ""} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		code := http.StatusOK
+		if want == "" {
+			code = http.StatusNoContent
+		}
+		if w.Code != code || strings.TrimSpace(w.Body.String()) != want {
+			t.Errorf("%s: expected %d %q, got %d %q", path, code, want, w.Code, w.Body.String())
+		}
+	}
+}
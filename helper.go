@@ -3,8 +3,6 @@ package gofast
 import (
 	"fmt"
 	"reflect"
-
-	"github.com/ugozlave/cargo"
 )
 
 type Lifetime int
@@ -15,85 +13,176 @@ const (
 	Transient
 )
 
+func (lt Lifetime) String() string {
+	switch lt {
+	case Singleton:
+		return "singleton"
+	case Scoped:
+		return "scoped"
+	case Transient:
+		return "transient"
+	default:
+		return fmt.Sprintf("Lifetime(%d)", int(lt))
+	}
+}
+
 const (
 	ScopeApplicationKeyFormat = "gofast-scope-application-%s"
 	ScopeRequestKeyFormat     = "gofast-scope-request-%s"
+	ScopeValidationId         = "validation"
 )
 
-func Register[K any, V any](app *App, builder func(*BuilderContext) V) {
-	ctn := app.container
+func Register[K any, V any](app *App, builder func(*BuilderContext) V, opts ...RegisterOption) {
+	RegisterKeyed[K](app, "", builder, opts...)
+}
+
+func RegisterKeyed[K any, V any](app *App, name string, builder func(*BuilderContext) V, opts ...RegisterOption) {
 	key := From[K]()
 	value := From[V]()
 	if !value.AssignableTo(key) {
 		panic(fmt.Sprintf("type %v is not assignable to %v", value, key))
 	}
-	ctn.Register(key.String(), value.String(), func(ctx cargo.BuilderContext) any {
-		return builder(NewBuilderContext(ctx, ctn))
-	})
+	app.register(service{key: key, name: name}, value, func(ctx *BuilderContext) any {
+		return builder(ctx)
+	}, opts...)
 }
 
-func Add[C Controller](app *App, builder func(*BuilderContext) C) {
-	Register[Controller](app, builder)
+func RegisterE[K any, V any](app *App, builder func(*BuilderContext) (V, error), opts ...RegisterOption) {
+	RegisterKeyedE[K](app, "", builder, opts...)
 }
 
-func Use[M Middleware](app *App, builder func(*BuilderContext) M) {
-	Register[Middleware](app, builder)
+func RegisterKeyedE[K any, V any](app *App, name string, builder func(*BuilderContext) (V, error), opts ...RegisterOption) {
+	key := From[K]()
+	value := From[V]()
+	if !value.AssignableTo(key) {
+		panic(fmt.Sprintf("type %v is not assignable to %v", value, key))
+	}
+	svc := service{key: key, name: name}
+	app.register(svc, value, func(ctx *BuilderContext) any {
+		v, err := builder(ctx)
+		if err != nil {
+			panic(svc.error(ctx, ctx.lifetime(), err))
+		}
+		return v
+	}, opts...)
 }
 
-func Cfg[C Config[T], T any](app *App, builder func(*BuilderContext) C) {
-	Register[Config[T]](app, builder)
+func Replace[K any, V any](app *App, builder func(*BuilderContext) V, opts ...RegisterOption) {
+	ReplaceKeyed[K](app, "", builder, opts...)
 }
 
-func Get[T any](ctx *BuilderContext, lt Lifetime) T {
-	ctn := ctx.container
-	key := From[T]()
-	var v T
-	switch lt {
-	case Singleton:
-		name := ctx.Name()
-		v = ctn.MustGet(key.String(), fmt.Sprintf(ScopeApplicationKeyFormat, name), ctx).(T)
-	case Scoped:
-		scope := ctx.RequestID()
-		v = ctn.MustGet(key.String(), fmt.Sprintf(ScopeRequestKeyFormat, scope), ctx).(T)
-	case Transient:
-		v = ctn.MustBuild(key.String(), ctx).(T)
+func ReplaceKeyed[K any, V any](app *App, name string, builder func(*BuilderContext) V, opts ...RegisterOption) {
+	RemoveKeyed[K](app, name)
+	RegisterKeyed[K](app, name, builder, opts...)
+}
+
+func TryRegister[K any, V any](app *App, builder func(*BuilderContext) V, opts ...RegisterOption) {
+	TryRegisterKeyed[K](app, "", builder, opts...)
+}
+
+func TryRegisterKeyed[K any, V any](app *App, name string, builder func(*BuilderContext) V, opts ...RegisterOption) {
+	if app.has(service{key: From[K](), name: name}) {
+		return
 	}
-	return v
+	RegisterKeyed[K](app, name, builder, opts...)
 }
 
-func MustGet[T any](ctx *BuilderContext, lt Lifetime) T {
-	ctn := ctx.container
-	key := From[T]()
-	var v T
-	switch lt {
-	case Singleton:
-		name := ctx.Name()
-		v = ctn.MustGet(key.String(), fmt.Sprintf(ScopeApplicationKeyFormat, name), ctx).(T)
-	case Scoped:
-		scope := ctx.RequestID()
-		v = ctn.MustGet(key.String(), fmt.Sprintf(ScopeRequestKeyFormat, scope), ctx).(T)
-	case Transient:
-		v = ctn.MustBuild(key.String(), ctx).(T)
+func Remove[K any](app *App) {
+	RemoveKeyed[K](app, "")
+}
+
+func RemoveKeyed[K any](app *App, name string) {
+	app.remove(service{key: From[K](), name: name}, func(*registration) bool {
+		return true
+	})
+}
+
+func RemoveImpl[K any, V any](app *App) {
+	value := From[V]()
+	app.remove(service{key: From[K]()}, func(reg *registration) bool {
+		return reg.value == value
+	})
+}
+
+func Decorate[T any](app *App, decorator func(*BuilderContext, T) T) {
+	DecorateKeyed(app, "", decorator)
+}
+
+func DecorateKeyed[T any](app *App, name string, decorator func(*BuilderContext, T) T) {
+	if decorator == nil {
+		panic("decorator function cannot be nil")
 	}
+	app.decorate(service{key: From[T](), name: name}, func(ctx *BuilderContext, instance any) any {
+		v, _ := instance.(T)
+		return decorator(ctx, v)
+	})
+}
+
+func Add[C Controller](app *App, builder func(*BuilderContext) C, opts ...RegisterOption) {
+	Register[Controller](app, builder, append([]RegisterOption{AsScoped}, opts...)...)
+}
+
+func Use[M Middleware](app *App, builder func(*BuilderContext) M, opts ...RegisterOption) {
+	Register[Middleware](app, builder, append([]RegisterOption{AsScoped}, opts...)...)
+}
+
+func Host[S HostedService](app *App, builder func(*BuilderContext) S, opts ...RegisterOption) {
+	Register[HostedService](app, builder, append([]RegisterOption{AsSingleton}, opts...)...)
+}
+
+func Rule[R ValidationRule](app *App, builder func(*BuilderContext) R, opts ...RegisterOption) {
+	Register[ValidationRule](app, builder, append([]RegisterOption{AsSingleton}, opts...)...)
+}
+
+func Cfg[C Config[T], T any](app *App, builder func(*BuilderContext) C, opts ...RegisterOption) {
+	Register[Config[T]](app, builder, append([]RegisterOption{AsSingleton}, opts...)...)
+}
+
+func Get[T any](ctx *BuilderContext, lt ...Lifetime) T {
+	return GetKeyed[T](ctx, "", lt...)
+}
+
+func GetKeyed[T any](ctx *BuilderContext, name string, lt ...Lifetime) T {
+	v, _ := resolve(ctx, service{key: From[T](), name: name}, lt).(T)
+	return v
+}
+
+func TryGet[T any](ctx *BuilderContext, lt ...Lifetime) (T, error) {
+	return TryGetKeyed[T](ctx, "", lt...)
+}
+
+func TryGetKeyed[T any](ctx *BuilderContext, name string, lt ...Lifetime) (T, error) {
+	instance, err := try_resolve(ctx, service{key: From[T](), name: name}, lt)
+	v, _ := instance.(T)
+	return v, err
+}
+
+func MustGet[T any](ctx *BuilderContext, lt ...Lifetime) T {
+	return MustGetKeyed[T](ctx, "", lt...)
+}
+
+func MustGetKeyed[T any](ctx *BuilderContext, name string, lt ...Lifetime) T {
+	v, _ := resolve(ctx, service{key: From[T](), name: name}, lt).(T)
 	if any(v) == nil {
 		panic(fmt.Sprintf("type %T is nil", new(T)))
 	}
 	return v
 }
 
-func GetLogger[S any](ctx *BuilderContext, lt Lifetime) Logger {
-	logger := Get[Logger](ctx, lt).With(LogService, From[S]())
-	switch lt {
-	case Scoped:
-		return logger.With(LogRequestId, ctx.RequestID())
-	default:
-		return logger
-	}
+func GetLogger[S any](ctx *BuilderContext, lt ...Lifetime) Logger {
+	return with_request(ctx, Get[Logger](ctx, lt...).With(LogService, From[S]()), lt)
 }
 
-func MustGetLogger[S any](ctx *BuilderContext, lt Lifetime) Logger {
-	logger := MustGet[Logger](ctx, lt).With(LogService, From[S]())
-	switch lt {
+func MustGetLogger[S any](ctx *BuilderContext, lt ...Lifetime) Logger {
+	return with_request(ctx, MustGet[Logger](ctx, lt...).With(LogService, From[S]()), lt)
+}
+
+func with_request(ctx *BuilderContext, logger Logger, lt []Lifetime) Logger {
+	owner := ctx.lifetime()
+	if len(lt) > 0 {
+		owner = lt[0]
+	}
+	switch owner {
 	case Scoped:
 		return logger.With(LogRequestId, ctx.RequestID())
 	default:
@@ -101,28 +190,20 @@ func MustGetLogger[S any](ctx *BuilderContext, lt Lifetime) Logger {
 	}
 }
 
-func GetConfig[C any](ctx *BuilderContext, lt Lifetime) Config[C] {
-	return Get[Config[C]](ctx, lt)
+func GetConfig[C any](ctx *BuilderContext, lt ...Lifetime) Config[C] {
+	return Get[Config[C]](ctx, lt...)
 }
 
-func MustGetConfig[C any](ctx *BuilderContext, lt Lifetime) Config[C] {
-	return MustGet[Config[C]](ctx, lt)
+func MustGetConfig[C any](ctx *BuilderContext, lt ...Lifetime) Config[C] {
+	return MustGet[Config[C]](ctx, lt...)
 }
 
-func All[T any](ctx *BuilderContext, lt Lifetime) []T {
-	ctn := ctx.container
-	key := From[T]()
-	var instances []any
-	switch lt {
-	case Singleton:
-		name := ctx.Name()
-		instances = ctn.Gets(key.String(), fmt.Sprintf(ScopeApplicationKeyFormat, name), ctx)
-	case Scoped:
-		scope := ctx.RequestID()
-		instances = ctn.Gets(key.String(), fmt.Sprintf(ScopeRequestKeyFormat, scope), ctx)
-	case Transient:
-		instances = ctn.Builds(key.String(), ctx)
-	}
+func All[T any](ctx *BuilderContext, lt ...Lifetime) []T {
+	return AllKeyed[T](ctx, "", lt...)
+}
+
+func AllKeyed[T any](ctx *BuilderContext, name string, lt ...Lifetime) []T {
+	instances := resolve_all(ctx, service{key: From[T](), name: name}, lt)
 	result := make([]T, 0, len(instances))
 	for _, instance := range instances {
 		result = append(result, instance.(T))
@@ -130,27 +211,78 @@ func All[T any](ctx *BuilderContext, lt Lifetime) []T {
 	return result
 }
 
+func resolve(ctx *BuilderContext, svc service, lts []Lifetime) any {
+	registrations := ctx.app.services[svc]
+	count := len(registrations)
+	if count < 1 {
+		if instance, ok := unwrap(ctx, svc, lts); ok {
+			return instance
+		}
+		panic(svc.error(ctx, lifetime_of(ctx, svc, lts), ErrNotFound))
+	}
+	reg := registrations[count-1]
+	lt := reg.resolve_lifetime(ctx, lts)
+	return reg.instance(ctx.enter(svc, lt), lt)
+}
+
+func try_resolve(ctx *BuilderContext, svc service, lts []Lifetime) (instance any, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = as_error(rec)
+			if re, ok := err.(*ResolveError); !ok || re.Type != svc.key || re.Name != svc.name {
+				err = svc.error(ctx, lifetime_of(ctx, svc, lts), err)
+			}
+		}
+	}()
+	return resolve(ctx, svc, lts), nil
+}
+
+func lifetime_of(ctx *BuilderContext, svc service, lts []Lifetime) Lifetime {
+	if registrations := ctx.app.services[svc]; len(registrations) > 0 {
+		return registrations[len(registrations)-1].resolve_lifetime(ctx, lts)
+	}
+	if len(lts) > 0 {
+		return lts[0]
+	}
+	return ctx.lifetime()
+}
+
+func resolve_all(ctx *BuilderContext, svc service, lts []Lifetime) []any {
+	registrations := ctx.app.services[svc]
+	instances := make([]any, 0, len(registrations))
+	for _, reg := range registrations {
+		lt := reg.resolve_lifetime(ctx, lts)
+		instances = append(instances, reg.instance(ctx.enter(svc, lt), lt))
+	}
+	return instances
+}
+
 func From[T any]() reflect.Type {
 	return reflect.TypeOf((*T)(nil)).Elem()
 }
 
 type Builder[T any] func(*BuilderContext) T
 
-func New() (*App, AppConfig) {
-	Environment.Read()
-	ConfigFiles.Add("config.json")
-	ConfigFiles.Add("config." + Environment.Get() + ".json")
-	ConfigFiles.Env(true)
-	cfg := NewConfig(AppConfig{Name: "gofast"}, CONFIG.APPLICATION_PATH...).Value()
-	app := Empty(&cfg)
+type BuilderE[T any] func(*BuilderContext) (T, error)
+
+func New(opts ...Option) (*App, AppConfig) {
+	o := new_options(true, opts)
+	cfg := new_config(o, defaultValidator, AppConfig{Name: "gofast"}, o.config.APPLICATION_PATH...).Value()
+	app := empty(&cfg, o)
+	Defaults(app)
+	return app, cfg
+}
+
+func Defaults(app *App) {
 	Cfg(app, ConfigBuilder(LoggerConfig{Level: "info"}))
 	Add(app, HealthControllerBuilder())
-	Use(app, LogMiddlewareBuilder())
-	Use(app, RecoverMiddlewareBuilder())
-	Use(app, TimeoutMiddlewareBuilder())
-	Use(app, BodyLimiterMiddlewareBuilder())
-	Register[UniqueIDGenerator](app, SequenceIDGeneratorBuilder())
-	Register[Logger](app, LoggerBuilder())
-	Register[Cache](app, MemoryCacheBuilder())
-	return app, cfg
+	Use(app, LogMiddlewareBuilder(), AsSingleton)
+	Use(app, RecoverMiddlewareBuilder(), AsSingleton)
+	Use(app, TimeoutMiddlewareBuilder(), AsSingleton)
+	Use(app, BodyLimiterMiddlewareBuilder(), AsSingleton)
+	Register[UniqueIDGenerator](app, SequenceIDGeneratorBuilder(), AsSingleton)
+	Register[Logger](app, LoggerBuilder(), AsSingleton)
+	Register[Cache](app, MemoryCacheBuilder(), AsSingleton)
+	Register[*Validator](app, ValidatorBuilder(), AsSingleton)
+	Register[ErrorRenderer](app, NegotiatingRendererBuilder(), AsSingleton)
 }
@@ -0,0 +1,347 @@
+package gofast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type cycleA struct{ b *cycleB }
+type cycleB struct{ a *cycleA }
+
+func newTestContext(app *App) *BuilderContext {
+	ctn := app.container
+	ctx := context.WithValue(app.context(context.Background()), CtxRequestId, "test")
+	ctn.CreateScope(fmt.Sprintf(ScopeApplicationKeyFormat, app.config.Name))
+	ctn.CreateScope(fmt.Sprintf(ScopeRequestKeyFormat, "test"))
+	return NewBuilderContext(ctx, ctn)
+}
+
+func newQuietApp(tb testing.TB, opts ...Option) *App {
+	app, _ := New(append([]Option{WithConfigFiles(), WithEnv(false)}, opts...)...)
+	tb.Cleanup(app.Close)
+	Replace[Config[LoggerConfig]](app, ConfigBuilder(LoggerConfig{Level: "info", Discard: true}), AsSingleton)
+	return app
+}
+
+func TestMustGet_CircularDependency(t *testing.T) {
+	app := Empty(&AppConfig{Name: "test"})
+	Register[*cycleA](app, func(ctx *BuilderContext) *cycleA {
+		return &cycleA{b: MustGet[*cycleB](ctx, Singleton)}
+	})
+	Register[*cycleB](app, func(ctx *BuilderContext) *cycleB {
+		return &cycleB{a: MustGet[*cycleA](ctx, Transient)}
+	})
+
+	defer func() {
+		var cycle *CycleError
+		err, _ := recover().(error)
+		if !errors.As(err, &cycle) {
+			t.Fatalf("Expected CycleError, got: %v", err)
+		}
+		want := "circular dependency: *gofast.cycleA -> *gofast.cycleB -> *gofast.cycleA"
+		if cycle.Error() != want {
+			t.Errorf("Expected %q, got %q", want, cycle.Error())
+		}
+	}()
+
+	MustGet[*cycleA](newTestContext(app), Singleton)
+	t.Fatal("Expected panic")
+}
+
+type captiveSingleton struct{ scoped *captiveScoped }
+type captiveScoped struct{}
+
+func TestGet_CaptiveDependency(t *testing.T) {
+	app := Empty(&AppConfig{Name: "test"}, WithDebug(true))
+	Register[*captiveSingleton](app, func(ctx *BuilderContext) *captiveSingleton {
+		return &captiveSingleton{scoped: Get[*captiveScoped](ctx, Scoped)}
+	})
+	Register[*captiveScoped](app, func(ctx *BuilderContext) *captiveScoped {
+		return &captiveScoped{}
+	})
+
+	ctx := newTestContext(app)
+
+	// scoped from scoped is fine
+	Get[*captiveSingleton](ctx, Scoped)
+
+	defer func() {
+		var captive *CaptiveError
+		err, _ := recover().(error)
+		if !errors.As(err, &captive) {
+			t.Fatalf("Expected CaptiveError, got: %v", err)
+		}
+		if captive.Lifetime != Scoped || captive.OwnerLifetime != Singleton {
+			t.Errorf("Unexpected lifetimes: %v", captive)
+		}
+	}()
+
+	Get[*captiveSingleton](ctx, Singleton)
+	t.Fatal("Expected panic")
+}
+
+type disposeLog struct{ order []string }
+type disposeCloser struct{ log *disposeLog }
+type disposeDisposable struct {
+	log    *disposeLog
+	closer *disposeCloser
+}
+
+func (c *disposeCloser) Close() error {
+	c.log.order = append(c.log.order, "closer")
+	return nil
+}
+
+func (d *disposeDisposable) Dispose(ctx context.Context) error {
+	d.log.order = append(d.log.order, "disposable")
+	return nil
+}
+
+func TestDeleteScope_DisposesInReverseOrder(t *testing.T) {
+	log := &disposeLog{}
+	app := Empty(&AppConfig{Name: "test"})
+	Register[*disposeCloser](app, func(ctx *BuilderContext) *disposeCloser {
+		return &disposeCloser{log: log}
+	})
+	Register[*disposeDisposable](app, func(ctx *BuilderContext) *disposeDisposable {
+		return &disposeDisposable{log: log, closer: MustGet[*disposeCloser](ctx, Scoped)}
+	})
+
+	ctx := newTestContext(app)
+	MustGet[*disposeDisposable](ctx, Scoped)
+	MustGet[*disposeDisposable](ctx, Transient)
+
+	app.container.DeleteScope(fmt.Sprintf(ScopeRequestKeyFormat, ctx.RequestID()))
+
+	want := []string{"disposable", "closer"}
+	if fmt.Sprint(log.order) != fmt.Sprint(want) {
+		t.Errorf("Expected dispose order %v, got %v", want, log.order)
+	}
+}
+
+type disposeFailing struct{}
+
+func (d *disposeFailing) Dispose(ctx context.Context) error {
+	return errors.New("flush failed")
+}
+
+func TestClose_ReleasesRequestScopesFirst(t *testing.T) {
+	for range 20 {
+		app := newQuietApp(t)
+		Register[*disposeFailing](app, func(ctx *BuilderContext) *disposeFailing {
+			return &disposeFailing{}
+		}, AsScoped)
+
+		ctx, _ := app.NewScope(context.Background())
+		MustGet[*disposeFailing](ctx)
+
+		func() {
+			defer func() {
+				if rec := recover(); rec != nil {
+					t.Fatalf("Expected Close not to panic, got: %v", rec)
+				}
+			}()
+			app.Close()
+		}()
+	}
+}
+
+type keyedDB struct{ dsn string }
+
+func TestGetKeyed(t *testing.T) {
+	app := Empty(&AppConfig{Name: "test"})
+	Register[*keyedDB](app, func(ctx *BuilderContext) *keyedDB {
+		return &keyedDB{dsn: "default"}
+	})
+	RegisterKeyed[*keyedDB](app, "primary", func(ctx *BuilderContext) *keyedDB {
+		return &keyedDB{dsn: "primary"}
+	})
+	RegisterKeyed[*keyedDB](app, "replica", func(ctx *BuilderContext) *keyedDB {
+		return &keyedDB{dsn: MustGetKeyed[*keyedDB](ctx, "primary", Singleton).dsn + "-replica"}
+	})
+
+	ctx := newTestContext(app)
+
+	if db := MustGet[*keyedDB](ctx, Singleton); db.dsn != "default" {
+		t.Errorf("Expected default, got %s", db.dsn)
+	}
+	if db := MustGetKeyed[*keyedDB](ctx, "primary", Singleton); db.dsn != "primary" {
+		t.Errorf("Expected primary, got %s", db.dsn)
+	}
+	if db := MustGetKeyed[*keyedDB](ctx, "replica", Scoped); db.dsn != "primary-replica" {
+		t.Errorf("Expected primary-replica, got %s", db.dsn)
+	}
+	if dbs := AllKeyed[*keyedDB](ctx, "replica", Scoped); len(dbs) != 1 {
+		t.Errorf("Expected 1 replica, got %d", len(dbs))
+	}
+}
+
+type decoratedCache struct {
+	Cache
+	name string
+}
+
+func TestDecorate(t *testing.T) {
+	app := newQuietApp(t)
+	Decorate(app, func(ctx *BuilderContext, c Cache) Cache {
+		return &decoratedCache{Cache: c, name: "first"}
+	})
+	Decorate(app, func(ctx *BuilderContext, c Cache) Cache {
+		return &decoratedCache{Cache: c, name: "second"}
+	})
+
+	ctx := newTestContext(app)
+
+	outer, ok := MustGet[Cache](ctx, Singleton).(*decoratedCache)
+	if !ok || outer.name != "second" {
+		t.Fatalf("Expected outermost decorator to be second, got %#v", outer)
+	}
+	inner, ok := outer.Cache.(*decoratedCache)
+	if !ok || inner.name != "first" {
+		t.Fatalf("Expected inner decorator to be first, got %#v", inner)
+	}
+	if _, ok := inner.Cache.(*MemoryCache); !ok {
+		t.Fatalf("Expected decorated service to be *MemoryCache, got %T", inner.Cache)
+	}
+	if outer != MustGet[Cache](ctx, Singleton) {
+		t.Error("Expected decorated singleton to be cached")
+	}
+}
+
+type replacedLogger struct{ Logger }
+
+func TestReplaceTryRegisterRemove(t *testing.T) {
+	app := newQuietApp(t)
+	Replace[Logger](app, func(ctx *BuilderContext) *replacedLogger {
+		return &replacedLogger{Logger: &FastLogger{logger: slog.Default()}}
+	})
+	TryRegister[Logger](app, LoggerBuilder())
+	TryRegister[Cache](app, MemoryCacheBuilder())
+	RemoveImpl[Middleware, *TimeoutMiddleware](app)
+	Remove[Controller](app)
+
+	ctx := newTestContext(app)
+
+	if _, ok := MustGet[Logger](ctx, Singleton).(*replacedLogger); !ok {
+		t.Error("Expected Logger to be replaced")
+	}
+	if loggers := All[Logger](ctx, Singleton); len(loggers) != 1 {
+		t.Errorf("Expected 1 Logger, got %d", len(loggers))
+	}
+	if caches := All[Cache](ctx, Singleton); len(caches) != 1 {
+		t.Errorf("Expected 1 Cache, got %d", len(caches))
+	}
+	for _, m := range All[Middleware](ctx) {
+		if _, ok := m.(*TimeoutMiddleware); ok {
+			t.Error("Expected TimeoutMiddleware to be removed")
+		}
+	}
+	if controllers := All[Controller](ctx, Scoped); len(controllers) != 0 {
+		t.Errorf("Expected no Controller, got %d", len(controllers))
+	}
+	if err := app.Validate(); err != nil {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+}
+
+type removeA struct{}
+type removeB struct{}
+
+func (removeA) Handle(next http.Handler) http.Handler { return next }
+func (removeB) Handle(next http.Handler) http.Handler { return next }
+
+func TestRemove_ThenRegister(t *testing.T) {
+	app := Empty(&AppConfig{Name: "test"})
+	Use(app, func(*BuilderContext) *TimeoutMiddleware { return &TimeoutMiddleware{} })
+	Use(app, func(*BuilderContext) removeA { return removeA{} })
+	RemoveImpl[Middleware, *TimeoutMiddleware](app)
+	Use(app, func(*BuilderContext) removeB { return removeB{} })
+
+	got := All[Middleware](newTestContext(app))
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 middlewares, got %d", len(got))
+	}
+	if _, ok := got[0].(removeA); !ok {
+		t.Errorf("Expected removeA first, got %T", got[0])
+	}
+	if _, ok := got[1].(removeB); !ok {
+		t.Errorf("Expected removeB second, got %T", got[1])
+	}
+}
+
+type lifetimeService struct{}
+
+type recordLogger struct {
+	Logger
+	warnings *[]string
+}
+
+func (l *recordLogger) Wrn(msg string, args ...any) {
+	*l.warnings = append(*l.warnings, msg)
+}
+
+func TestGet_RegisteredLifetime(t *testing.T) {
+	var warnings []string
+	app := Empty(&AppConfig{Name: "test"})
+	Register[Logger](app, func(ctx *BuilderContext) *recordLogger {
+		return &recordLogger{Logger: &FastLogger{logger: slog.Default()}, warnings: &warnings}
+	}, AsSingleton)
+	Register[*lifetimeService](app, func(ctx *BuilderContext) *lifetimeService {
+		return &lifetimeService{}
+	}, AsSingleton)
+
+	ctx := newTestContext(app)
+	svc := MustGet[*lifetimeService](ctx)
+
+	if svc != MustGet[*lifetimeService](ctx) {
+		t.Error("Expected registered singleton to be reused")
+	}
+	if svc != MustGet[*lifetimeService](ctx, Transient) {
+		t.Error("Expected registered lifetime to win over the requested one")
+	}
+	MustGet[*lifetimeService](ctx, Transient)
+
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "lifetime conflict") {
+		t.Errorf("Expected a single lifetime conflict warning, got %v", warnings)
+	}
+}
+
+type tryDialer struct{}
+type tryRepository struct{ dialer *tryDialer }
+
+func TestTryGet(t *testing.T) {
+	cause := errors.New("connection refused")
+	app := Empty(&AppConfig{Name: "test"})
+	RegisterE[*tryDialer](app, func(ctx *BuilderContext) (*tryDialer, error) {
+		return nil, cause
+	}, AsSingleton)
+	Register[*tryRepository](app, func(ctx *BuilderContext) *tryRepository {
+		return &tryRepository{dialer: MustGet[*tryDialer](ctx)}
+	}, AsScoped)
+
+	ctx := newTestContext(app)
+
+	_, err := TryGet[validationMissing](ctx, Scoped)
+	var re *ResolveError
+	if !errors.Is(err, ErrNotFound) || !errors.As(err, &re) {
+		t.Fatalf("Expected not found ResolveError, got: %v", err)
+	}
+	if re.Type != From[validationMissing]() || re.Scope != "gofast-scope-request-test" {
+		t.Errorf("Unexpected ResolveError: %#v", re)
+	}
+
+	_, err = TryGet[*tryDialer](ctx)
+	if !errors.Is(err, cause) || !errors.As(err, &re) || re.Scope != "gofast-scope-application-test" {
+		t.Errorf("Expected builder error in application scope, got: %v", err)
+	}
+
+	_, err = TryGet[*tryRepository](ctx)
+	if !errors.Is(err, cause) || !errors.As(err, &re) || re.Type != From[*tryRepository]() {
+		t.Errorf("Expected error for the requested type, got: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package gofast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+type HostedService interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+type CriticalService interface {
+	Critical() bool
+}
+
+type FailingService interface {
+	Failed() <-chan error
+}
+
+func start_services(ctx context.Context, logger Logger, services []HostedService) ([]HostedService, error) {
+	started := make([]HostedService, 0, len(services))
+	for _, service := range services {
+		name := reflect.TypeOf(service).String()
+		if err := service.Start(ctx); err != nil {
+			logger.Err("hosted service start failed", LogHosted, name, LogError, err)
+			if critical, ok := service.(CriticalService); ok && critical.Critical() {
+				return started, err
+			}
+			continue
+		}
+		logger.Dbg("hosted service started", LogHosted, name)
+		started = append(started, service)
+	}
+	return started, nil
+}
+
+func stop_services(ctx context.Context, logger Logger, services []HostedService) error {
+	var errs []error
+	for _, service := range slices.Backward(services) {
+		name := reflect.TypeOf(service).String()
+		if err := service.Stop(ctx); err != nil {
+			logger.Err("hosted service stop failed", LogHosted, name, LogError, err)
+			errs = append(errs, err)
+			continue
+		}
+		logger.Dbg("hosted service stopped", LogHosted, name)
+	}
+	return errors.Join(errs...)
+}
+
+func watch_services(ctx context.Context, logger Logger, services []HostedService, failed chan<- error) {
+	for _, service := range services {
+		fs, ok := service.(FailingService)
+		if !ok {
+			continue
+		}
+		name := reflect.TypeOf(service).String()
+		go func() {
+			select {
+			case <-ctx.Done():
+			case err, ok := <-fs.Failed():
+				if !ok || err == nil {
+					return
+				}
+				logger.Err("hosted service failed", LogHosted, name, LogError, err)
+				if critical, ok := service.(CriticalService); ok && critical.Critical() {
+					select {
+					case failed <- fmt.Errorf("hosted service failed: %w", err):
+					case <-ctx.Done():
+					}
+				}
+			}
+		}()
+	}
+}
@@ -0,0 +1,146 @@
+package gofast
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var errorType = From[error]()
+
+func RegisterCtor[K any](app *App, lt Lifetime, ctor any, opts ...RegisterOption) {
+	key := From[K]()
+	fn := reflect.ValueOf(ctor)
+	ft := fn.Type()
+	if ft.Kind() != reflect.Func {
+		panic(fmt.Sprintf("constructor %v is not a function", ft))
+	}
+	if ft.IsVariadic() {
+		panic(fmt.Sprintf("constructor %v cannot be variadic", ft))
+	}
+	if ft.NumOut() < 1 || ft.NumOut() > 2 || (ft.NumOut() == 2 && ft.Out(1) != errorType) {
+		panic(fmt.Sprintf("constructor %v must return a value and an optional error", ft))
+	}
+	value := ft.Out(0)
+	if !value.AssignableTo(key) {
+		panic(fmt.Sprintf("type %v is not assignable to %v", value, key))
+	}
+	svc := service{key: key}
+	app.register(svc, value, func(ctx *BuilderContext) any {
+		args := make([]reflect.Value, ft.NumIn())
+		for i := range args {
+			args[i] = argument(ctx, ft.In(i))
+		}
+		out := fn.Call(args)
+		if len(out) == 2 && !out[1].IsNil() {
+			panic(svc.error(ctx, ctx.lifetime(), out[1].Interface().(error)))
+		}
+		return out[0].Interface()
+	}, append([]RegisterOption{lifetimeOption(lt)}, opts...)...)
+}
+
+func argument(ctx *BuilderContext, param reflect.Type) reflect.Value {
+	if param == reflect.TypeOf(ctx) {
+		return reflect.ValueOf(ctx)
+	}
+	instance := resolve(ctx, service{key: param}, nil)
+	if instance == nil {
+		return reflect.Zero(param)
+	}
+	return reflect.ValueOf(instance)
+}
+
+func RegisterStruct[K any, V any](app *App, opts ...RegisterOption) {
+	Register[K](app, StructBuilder[V](), opts...)
+}
+
+func StructBuilder[V any]() Builder[V] {
+	value := From[V]()
+	elem := value
+	if elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("type %v is not a struct", value))
+	}
+	fields := struct_fields(elem)
+	return func(ctx *BuilderContext) V {
+		ptr := reflect.New(elem)
+		for _, f := range fields {
+			f.inject(ctx, ptr.Elem())
+		}
+		if value.Kind() == reflect.Pointer {
+			return ptr.Interface().(V)
+		}
+		return ptr.Elem().Interface().(V)
+	}
+}
+
+type injection struct {
+	owner    reflect.Type
+	field    reflect.StructField
+	name     string
+	lifetime Lifetime
+	explicit bool
+}
+
+func struct_fields(t reflect.Type) []injection {
+	fields := make([]injection, 0, t.NumField())
+	for _, field := range reflect.VisibleFields(t) {
+		tag, ok := field.Tag.Lookup("inject")
+		if !ok || len(field.Index) > 1 {
+			continue
+		}
+		if !field.IsExported() {
+			panic(fmt.Sprintf("field %v.%s is not exported", t, field.Name))
+		}
+		f := injection{owner: t, field: field}
+		for opt := range strings.SplitSeq(tag, ",") {
+			opt = strings.TrimSpace(opt)
+			switch {
+			case opt == "":
+			case opt == "singleton":
+				f.lifetime, f.explicit = Singleton, true
+			case opt == "scoped":
+				f.lifetime, f.explicit = Scoped, true
+			case opt == "transient":
+				f.lifetime, f.explicit = Transient, true
+			case strings.HasPrefix(opt, "name="):
+				f.name = strings.TrimPrefix(opt, "name=")
+			default:
+				panic(fmt.Sprintf("field %v.%s has unknown inject option %q", t, field.Name, opt))
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func (f injection) inject(ctx *BuilderContext, target reflect.Value) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			if err, ok := rec.(error); ok {
+				panic(fmt.Errorf("field %v.%s: %w", f.owner, f.field.Name, err))
+			}
+			panic(fmt.Errorf("field %v.%s: %v", f.owner, f.field.Name, rec))
+		}
+	}()
+	var lt []Lifetime
+	if f.explicit {
+		lt = []Lifetime{f.lifetime}
+	}
+	field := target.FieldByIndex(f.field.Index)
+	svc := service{key: f.field.Type, name: f.name}
+	if f.field.Type.Kind() == reflect.Slice && !ctx.app.has(svc) {
+		instances := resolve_all(ctx, service{key: f.field.Type.Elem(), name: f.name}, lt)
+		slice := reflect.MakeSlice(f.field.Type, 0, len(instances))
+		for _, instance := range instances {
+			slice = reflect.Append(slice, reflect.ValueOf(instance))
+		}
+		field.Set(slice)
+		return
+	}
+	if instance := resolve(ctx, svc, lt); instance != nil {
+		field.Set(reflect.ValueOf(instance))
+	}
+}
@@ -0,0 +1,130 @@
+package gofast
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type ctorService struct {
+	logger Logger
+	cache  Cache
+	config Config[LoggerConfig]
+}
+
+type ctorFailing struct{}
+
+func TestRegisterCtor(t *testing.T) {
+	app := newQuietApp(t)
+	RegisterCtor[*ctorService](app, Singleton, func(l Logger, c Cache, cfg Config[LoggerConfig]) *ctorService {
+		return &ctorService{logger: l, cache: c, config: cfg}
+	})
+
+	ctx := newTestContext(app)
+	svc := MustGet[*ctorService](ctx, Scoped)
+
+	if svc.logger != MustGet[Logger](ctx, Singleton) {
+		t.Error("Expected Logger to be resolved as singleton")
+	}
+	if svc.cache != MustGet[Cache](ctx, Singleton) {
+		t.Error("Expected Cache to be resolved as singleton")
+	}
+	if svc.config.Value().Level != "info" {
+		t.Errorf("Expected config level info, got %s", svc.config.Value().Level)
+	}
+}
+
+func TestRegisterCtor_Lifetime(t *testing.T) {
+	var warnings []string
+	app := newQuietApp(t)
+	Replace[Logger](app, func(ctx *BuilderContext) *recordLogger {
+		return &recordLogger{Logger: &FastLogger{logger: slog.Default()}, warnings: &warnings}
+	}, AsSingleton)
+	RegisterCtor[*ctorService](app, Scoped, func(l Logger, c Cache) *ctorService {
+		return &ctorService{logger: l, cache: c}
+	})
+
+	ctx := newTestContext(app)
+	svc := MustGet[*ctorService](ctx)
+
+	if svc != MustGet[*ctorService](ctx) {
+		t.Error("Expected scoped constructor to be reused within a scope")
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Expected no lifetime conflict warnings, got %v", warnings)
+	}
+}
+
+func TestRegisterCtor_Error(t *testing.T) {
+	cause := errors.New("dial failed")
+	app := Empty(&AppConfig{Name: "test"})
+	RegisterCtor[*ctorFailing](app, Transient, func(ctx *BuilderContext) (*ctorFailing, error) {
+		return nil, cause
+	})
+
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, cause) {
+			t.Fatalf("Expected constructor error, got: %v", err)
+		}
+	}()
+
+	MustGet[*ctorFailing](newTestContext(app), Transient)
+	t.Fatal("Expected panic")
+}
+
+type structService struct {
+	Logger   Logger               `inject:""`
+	Cache    Cache                `inject:"singleton"`
+	Primary  *keyedDB             `inject:"name=primary,transient"`
+	Checkers []HealthChecker      `inject:"scoped"`
+	Config   Config[LoggerConfig] `inject:"singleton"`
+	Ignored  string
+}
+
+type structMissing struct {
+	Missing validationMissing `inject:""`
+}
+
+func TestRegisterStruct(t *testing.T) {
+	app := newQuietApp(t)
+	RegisterKeyed[*keyedDB](app, "primary", func(ctx *BuilderContext) *keyedDB {
+		return &keyedDB{dsn: "primary"}
+	})
+	RegisterStruct[*structService, *structService](app)
+
+	ctx := newTestContext(app)
+	svc := MustGet[*structService](ctx, Scoped)
+
+	if svc.Logger != MustGet[Logger](ctx) {
+		t.Error("Expected Logger to be resolved with its registered lifetime")
+	}
+	if svc.Cache != MustGet[Cache](ctx, Singleton) {
+		t.Error("Expected Cache to be resolved as singleton")
+	}
+	if svc.Primary == nil || svc.Primary.dsn != "primary" {
+		t.Errorf("Expected keyed primary, got %v", svc.Primary)
+	}
+	if svc.Checkers == nil || len(svc.Checkers) != 0 {
+		t.Errorf("Expected empty checkers, got %v", svc.Checkers)
+	}
+	if svc.Config == nil {
+		t.Error("Expected Config to be injected")
+	}
+}
+
+func TestRegisterStruct_MissingField(t *testing.T) {
+	app := Empty(&AppConfig{Name: "test"})
+	RegisterStruct[*structMissing, *structMissing](app)
+
+	defer func() {
+		err, _ := recover().(error)
+		if err == nil || !strings.Contains(err.Error(), "field gofast.structMissing.Missing") {
+			t.Fatalf("Expected error naming the field, got: %v", err)
+		}
+	}()
+
+	MustGet[*structMissing](newTestContext(app), Scoped)
+	t.Fatal("Expected panic")
+}
@@ -5,58 +5,50 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"slices"
 	"strings"
 
 	"github.com/ugozlave/cargo"
 )
 
 type HttpInjector struct {
-	ctn *cargo.Container
-	gen UniqueIDGenerator
+	app     *App
+	ctn     *cargo.Container
+	gen     UniqueIDGenerator
+	handler http.Handler
 }
 
 func (inj *HttpInjector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// create unique request ID
 	id := inj.gen.Next()
 
-	// create unique scope for the request
-	scope := fmt.Sprintf(ScopeRequestKeyFormat, id)
-	inj.ctn.CreateScope(scope)
-	defer func() {
-		inj.ctn.DeleteScope(scope)
-	}()
+	// attach the application when served outside of Run
+	parent := r.Context()
+	if parent.Value(CtxApp) == nil {
+		parent = inj.app.context(parent)
+	}
 
 	// create a new builder context
-	ctx := NewBuilderContext(context.WithValue(r.Context(), CtxRequestId, id), inj.ctn)
-
-	// build controllers
-	handler := inj.Controllers(ctx)
-
-	// build middlewares
-	use := inj.Middlewares(ctx)
+	parent = context.WithValue(parent, CtxPath, r.URL.Path)
+	ctx := NewBuilderContext(context.WithValue(parent, CtxRequestId, id), inj.ctn)
 
-	mux := use(handler)
+	// create unique scope for the request
+	scope := fmt.Sprintf(ScopeRequestKeyFormat, id)
+	create_scope(ctx, scope)
+	defer delete_scope(ctx, scope)
 
-	mux.ServeHTTP(w, r.WithContext(ctx))
+	// serve through the precompiled routes and middlewares
+	inj.handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
+// Deprecated: routes are compiled once by App.Handler, ServeHTTP no longer uses this.
 func (inj *HttpInjector) Controllers(ctx *BuilderContext) http.Handler {
-	mux := http.NewServeMux()
-	for _, ctrl := range All[Controller](ctx, Scoped) {
-		prefix := strings.Trim(ctrl.Prefix(), "/")
-		mux.Handle("/"+prefix, strip_prefix("/"+prefix, ctrl.Routes()))
-		mux.Handle("/"+prefix+"/", strip_prefix("/"+prefix, ctrl.Routes()))
-	}
-	return mux
+	return inj.app.controllers(ctx)
 }
 
+// Deprecated: middlewares are compiled once by App.Handler, ServeHTTP no longer uses this.
 func (inj *HttpInjector) Middlewares(ctx *BuilderContext) func(http.Handler) http.Handler {
-	return func(mux http.Handler) http.Handler {
-		for _, mid := range slices.Backward(All[Middleware](ctx, Scoped)) {
-			mux = mid.Handle(mux)
-		}
-		return mux
+	return func(next http.Handler) http.Handler {
+		return inj.app.middlewares(ctx, next)
 	}
 }
 
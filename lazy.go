@@ -0,0 +1,56 @@
+package gofast
+
+import (
+	"reflect"
+	"sync"
+)
+
+type wrapper interface {
+	wrap(ctx *BuilderContext, svc service, lts []Lifetime) any
+}
+
+/*
+** Lazy
+ */
+
+type Lazy[T any] struct {
+	value func() T
+}
+
+func (l Lazy[T]) Value() T {
+	if l.value == nil {
+		var zero T
+		return zero
+	}
+	return l.value()
+}
+
+func (Lazy[T]) wrap(ctx *BuilderContext, svc service, lts []Lifetime) any {
+	ctx = ctx.detach(svc)
+	return Lazy[T]{value: sync.OnceValue(func() T {
+		return MustGetKeyed[T](ctx, svc.name, lts...)
+	})}
+}
+
+/*
+** Factory
+ */
+
+type Factory[T any] func() T
+
+func (Factory[T]) wrap(ctx *BuilderContext, svc service, lts []Lifetime) any {
+	ctx = ctx.detach(svc)
+	return Factory[T](func() T {
+		// fresh unless the registration declares a longer lifetime
+		v, _ := resolve(ctx, service{key: From[T](), name: svc.name}, []Lifetime{Transient}).(T)
+		return v
+	})
+}
+
+func unwrap(ctx *BuilderContext, svc service, lts []Lifetime) (any, bool) {
+	w, ok := reflect.Zero(svc.key).Interface().(wrapper)
+	if !ok {
+		return nil, false
+	}
+	return w.wrap(ctx, svc, lts), true
+}
@@ -0,0 +1,79 @@
+package gofast
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type lazyExpensive struct{ id int }
+
+type lazyFresh struct{ id int }
+
+type lazyOwner struct {
+	Expensive Lazy[*lazyExpensive] `inject:""`
+	New       Factory[*lazyFresh]  `inject:""`
+}
+
+func TestLazyAndFactory(t *testing.T) {
+	built := 0
+	app := Empty(&AppConfig{Name: "test"})
+	Register[*lazyExpensive](app, func(ctx *BuilderContext) *lazyExpensive {
+		built++
+		return &lazyExpensive{id: built}
+	}, AsScoped)
+	Register[*lazyFresh](app, func(ctx *BuilderContext) *lazyFresh {
+		built++
+		return &lazyFresh{id: built}
+	})
+	RegisterStruct[*lazyOwner, *lazyOwner](app, AsScoped)
+
+	ctx := newTestContext(app)
+	owner := MustGet[*lazyOwner](ctx)
+
+	if built != 0 {
+		t.Fatalf("Expected no instance before Value(), got %d", built)
+	}
+	if owner.Expensive.Value() != owner.Expensive.Value() || built != 1 {
+		t.Errorf("Expected Lazy to resolve once, built %d", built)
+	}
+	if owner.Expensive.Value() != MustGet[*lazyExpensive](ctx) {
+		t.Error("Expected Lazy to honor the registered lifetime")
+	}
+	if a, b := owner.New(), owner.New(); a == b || built != 3 {
+		t.Errorf("Expected Factory to build fresh instances, built %d", built)
+	}
+}
+
+type lazySingleton struct{ closed int }
+
+func (s *lazySingleton) Close() error {
+	s.closed++
+	return nil
+}
+
+func TestFactory_RegisteredLifetime(t *testing.T) {
+	var warnings []string
+	app := Empty(&AppConfig{Name: "test"})
+	Register[Logger](app, func(ctx *BuilderContext) *recordLogger {
+		return &recordLogger{Logger: &FastLogger{logger: slog.Default()}, warnings: &warnings}
+	}, AsSingleton)
+	Register[*lazySingleton](app, func(ctx *BuilderContext) *lazySingleton {
+		return &lazySingleton{}
+	}, AsSingleton)
+
+	ctx := newTestContext(app)
+	f := MustGet[Factory[*lazySingleton]](ctx)
+	s := f()
+
+	if s != f() || s != MustGet[*lazySingleton](ctx) {
+		t.Error("Expected Factory to honor the registered singleton")
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "lifetime conflict") {
+		t.Errorf("Expected a single lifetime conflict warning, got %v", warnings)
+	}
+	app.Close()
+	if s.closed != 1 {
+		t.Errorf("Expected singleton to be disposed once, got %d", s.closed)
+	}
+}
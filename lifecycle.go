@@ -0,0 +1,65 @@
+package gofast
+
+import (
+	"errors"
+)
+
+type Hook func(*BuilderContext) error
+
+const (
+	PhaseStarting string = "starting"
+	PhaseStarted  string = "started"
+	PhaseStopping string = "stopping"
+	PhaseStopped  string = "stopped"
+)
+
+type lifecycle struct {
+	hooks map[string][]Hook
+}
+
+func (app *App) OnStarting(hook Hook) {
+	app.on(PhaseStarting, hook)
+}
+
+func (app *App) OnStarted(hook Hook) {
+	app.on(PhaseStarted, hook)
+}
+
+func (app *App) OnStopping(hook Hook) {
+	app.on(PhaseStopping, hook)
+}
+
+func (app *App) OnStopped(hook Hook) {
+	app.on(PhaseStopped, hook)
+}
+
+func (app *App) on(phase string, hook Hook) {
+	if hook == nil {
+		panic("hook function cannot be nil")
+	}
+	if app.lifecycle.hooks == nil {
+		app.lifecycle.hooks = make(map[string][]Hook)
+	}
+	app.lifecycle.hooks[phase] = append(app.lifecycle.hooks[phase], hook)
+}
+
+func (l *lifecycle) start(ctx *BuilderContext, logger Logger, phase string) error {
+	for _, hook := range l.hooks[phase] {
+		if err := hook(ctx); err != nil {
+			logger.Err("lifecycle hook failed", LogPhase, phase, LogError, err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *lifecycle) stop(ctx *BuilderContext, logger Logger, phase string) error {
+	var errs []error
+	for _, hook := range l.hooks[phase] {
+		if err := hook(ctx); err != nil {
+			logger.Err("lifecycle hook failed", LogPhase, phase, LogError, err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -22,6 +22,10 @@ const (
 	LogAgent       string = "agent"
 	LogStatus      string = "status"
 	LogDuration    string = "duration"
+	LogHosted      string = "hosted"
+	LogError       string = "error"
+	LogPhase       string = "phase"
+	LogAddress     string = "address"
 )
 
 type Logger interface {
@@ -53,8 +57,8 @@ type LoggerBuilderOptions struct {
 
 func LoggerBuilder() Builder[*FastLogger] {
 	return func(ctx *BuilderContext) *FastLogger {
-		cfg := MustGetConfig[LoggerConfig](ctx, Singleton).Value()
-		env := Environment.Get()
+		cfg := MustGetConfig[LoggerConfig](ctx).Value()
+		env := ctx.Environment()
 		hostname, _ := os.Hostname()
 		name := ctx.Name()
 		level := slog.LevelInfo
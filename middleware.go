@@ -1,9 +1,13 @@
 package gofast
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"maps"
 	"net/http"
 	"runtime/debug"
+	"sync"
 	"time"
 )
 
@@ -16,19 +20,25 @@ type Middleware interface {
  */
 
 type BodyLimiterMiddleware struct {
-	Limit int64
+	Limit    int64
+	renderer ErrorRenderer
 }
 
 func BodyLimiterMiddlewareBuilder() Builder[*BodyLimiterMiddleware] {
-	return func(*BuilderContext) *BodyLimiterMiddleware {
+	return func(ctx *BuilderContext) *BodyLimiterMiddleware {
 		return &BodyLimiterMiddleware{
-			Limit: 1 << 20, // 1MB
+			Limit:    1 << 20, // 1MB
+			renderer: renderer_of(ctx),
 		}
 	}
 }
 
 func (m *BodyLimiterMiddleware) Handle(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > m.Limit {
+			m.renderer.Render(w, r, AsHTTPError(&http.MaxBytesError{Limit: m.Limit}))
+			return
+		}
 		r.Body = http.MaxBytesReader(w, r.Body, m.Limit)
 		next.ServeHTTP(w, r)
 	})
@@ -45,7 +55,7 @@ type LogMiddleware struct {
 func LogMiddlewareBuilder() Builder[*LogMiddleware] {
 	return func(ctx *BuilderContext) *LogMiddleware {
 		return &LogMiddleware{
-			logger: MustGetLogger[LogMiddleware](ctx, Scoped),
+			logger: MustGetLogger[LogMiddleware](ctx),
 		}
 	}
 }
@@ -55,6 +65,7 @@ func (m *LogMiddleware) Handle(next http.Handler) http.Handler {
 		t := time.Now()
 		writer := &writer{ResponseWriter: w}
 		group := m.logger.
+			With(LogRequestId, r.Context().Value(CtxRequestId)).
 			WithGroup("http").
 			With(
 				LogMethod, r.Method,
@@ -89,13 +100,17 @@ func (w *writer) WriteHeader(code int) {
  */
 
 type RecoverMiddleware struct {
-	logger Logger
+	logger   Logger
+	renderer ErrorRenderer
+	debug    bool
 }
 
 func RecoverMiddlewareBuilder() Builder[*RecoverMiddleware] {
 	return func(ctx *BuilderContext) *RecoverMiddleware {
 		return &RecoverMiddleware{
-			logger: MustGetLogger[RecoverMiddleware](ctx, Scoped),
+			logger:   MustGetLogger[RecoverMiddleware](ctx),
+			renderer: renderer_of(ctx),
+			debug:    ctx.Settings().DEBUG,
 		}
 	}
 }
@@ -105,11 +120,15 @@ func (m *RecoverMiddleware) Handle(next http.Handler) http.Handler {
 		defer func() {
 			if rec := recover(); rec != nil {
 				msg := fmt.Sprintf("panic: %s", rec)
-				if SETTINGS.DEBUG {
+				if m.debug {
 					msg += fmt.Sprintf("\n\n%s", debug.Stack())
 				}
-				m.logger.Err(msg)
-				http.Error(w, msg, http.StatusInternalServerError)
+				m.logger.Err(msg, LogRequestId, r.Context().Value(CtxRequestId))
+				err := &HTTPError{Status: http.StatusInternalServerError, Err: as_error(rec)}
+				if m.debug {
+					err.Detail = msg
+				}
+				m.renderer.Render(w, r, err)
 			}
 		}()
 		next.ServeHTTP(w, r)
@@ -121,17 +140,88 @@ func (m *RecoverMiddleware) Handle(next http.Handler) http.Handler {
  */
 
 type TimeoutMiddleware struct {
-	Timeout time.Duration
+	Timeout  time.Duration
+	renderer ErrorRenderer
 }
 
 func TimeoutMiddlewareBuilder() Builder[*TimeoutMiddleware] {
 	return func(ctx *BuilderContext) *TimeoutMiddleware {
 		return &TimeoutMiddleware{
-			Timeout: 30 * time.Second,
+			Timeout:  30 * time.Second,
+			renderer: renderer_of(ctx),
 		}
 	}
 }
 
 func (m *TimeoutMiddleware) Handle(next http.Handler) http.Handler {
-	return http.TimeoutHandler(next, m.Timeout, "Timeout")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), m.Timeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+
+		tw := &timeoutWriter{header: make(http.Header)}
+		done := make(chan struct{})
+		panicked := make(chan any, 1)
+		go func() {
+			defer func() {
+				if rec := recover(); rec != nil {
+					panicked <- rec
+				}
+			}()
+			next.ServeHTTP(tw, r)
+			close(done)
+		}()
+
+		select {
+		case rec := <-panicked:
+			panic(rec)
+		case <-done:
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			maps.Copy(w.Header(), tw.header)
+			if tw.status == 0 {
+				tw.status = http.StatusOK
+			}
+			w.WriteHeader(tw.status)
+			w.Write(tw.body.Bytes())
+		case <-ctx.Done():
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			tw.timedOut = true
+			m.renderer.Render(w, r, &HTTPError{Status: http.StatusServiceUnavailable, Detail: "request timed out", Err: ctx.Err()})
+		}
+	})
+}
+
+type timeoutWriter struct {
+	mu       sync.Mutex
+	header   http.Header
+	body     bytes.Buffer
+	status   int
+	timedOut bool
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut || w.status != 0 {
+		return
+	}
+	w.status = code
 }
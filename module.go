@@ -0,0 +1,82 @@
+package gofast
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type Module interface {
+	Configure(app *App)
+}
+
+type ModuleRequirements interface {
+	Requires() []Module
+}
+
+type KeyedModule interface {
+	ModuleKey() string
+}
+
+type moduleKey struct {
+	key  reflect.Type
+	name string
+}
+
+func (app *App) Install(modules ...Module) {
+	for _, module := range modules {
+		app.install(module)
+	}
+}
+
+func (app *App) install(module Module) {
+	if module == nil {
+		panic("module cannot be nil")
+	}
+	t := reflect.TypeOf(module)
+	key := moduleKey{key: t}
+	if keyed, ok := module.(KeyedModule); ok {
+		key.name = keyed.ModuleKey()
+	}
+	if app.installed == nil {
+		app.installed = make(map[moduleKey]bool)
+	}
+	if done, ok := app.installed[key]; ok {
+		if !done {
+			panic(fmt.Sprintf("module %v requires itself", t))
+		}
+		return
+	}
+	app.installed[key] = false
+	if requirements, ok := module.(ModuleRequirements); ok {
+		for _, required := range requirements.Requires() {
+			app.install(required)
+		}
+	}
+	if resolver, ok := module.(ConfigResolver); ok {
+		// value modules are decoded into a copy, which is then configured
+		target := reflect.ValueOf(module)
+		if t.Kind() != reflect.Pointer {
+			target = reflect.New(t)
+			target.Elem().Set(reflect.ValueOf(module))
+		}
+		if err := app.options.read(target.Interface(), resolver.Path()...); err != nil {
+			panic(fmt.Sprintf("invalid config %T: %v", module, err))
+		}
+		if t.Kind() != reflect.Pointer {
+			module = target.Elem().Interface().(Module)
+		}
+	}
+	name := app.module
+	app.module = key.String()
+	app.modules = append(app.modules, app.module)
+	module.Configure(app)
+	app.module = name
+	app.installed[key] = true
+}
+
+func (k moduleKey) String() string {
+	if k.name == "" {
+		return k.key.String()
+	}
+	return fmt.Sprintf("%v[%s]", k.key, k.name)
+}
@@ -0,0 +1,139 @@
+package gofast
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type moduleDatabase struct {
+	DSN      string `json:"DSN"`
+	installs int
+}
+
+func (m *moduleDatabase) Path() []string {
+	return []string{"Database"}
+}
+
+func (m *moduleDatabase) Configure(app *App) {
+	m.installs++
+	RegisterKeyed[*keyedDB](app, "primary", func(ctx *BuilderContext) *keyedDB {
+		return &keyedDB{dsn: m.DSN}
+	}, AsSingleton)
+}
+
+type moduleRepository struct {
+	db *moduleDatabase
+}
+
+func (m *moduleRepository) Requires() []Module {
+	return []Module{m.db}
+}
+
+func (m *moduleRepository) Configure(app *App) {
+	Register[*structService](app, StructBuilder[*structService](), AsScoped)
+}
+
+func TestApp_Install(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte(`{"Database":{"DSN":"postgres://primary"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	db := &moduleDatabase{}
+	app := newQuietApp(t, WithConfigFiles(file))
+	app.Install(&moduleRepository{db: db}, db)
+
+	if db.installs != 1 {
+		t.Errorf("Expected module to be installed once, got %d", db.installs)
+	}
+	if db.DSN != "postgres://primary" {
+		t.Errorf("Expected module config section to be loaded, got %q", db.DSN)
+	}
+	if app.modules[0] != "*gofast.moduleDatabase" || app.modules[1] != "*gofast.moduleRepository" {
+		t.Errorf("Expected required module to be installed first, got %v", app.modules)
+	}
+	if err := app.Validate(); err != nil {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+	if svc := MustGet[*structService](newTestContext(app)); svc.Primary.dsn != "postgres://primary" {
+		t.Errorf("Expected module registrations to resolve, got %q", svc.Primary.dsn)
+	}
+}
+
+type moduleNamed struct{ Name string }
+
+func (m moduleNamed) ModuleKey() string {
+	return m.Name
+}
+
+func (m moduleNamed) Configure(app *App) {
+	RegisterKeyed[*keyedDB](app, m.Name, func(ctx *BuilderContext) *keyedDB {
+		return &keyedDB{dsn: m.Name}
+	}, AsSingleton)
+}
+
+type moduleOrders struct{}
+
+func (m *moduleOrders) Requires() []Module {
+	return []Module{&moduleDatabase{}}
+}
+
+func (m *moduleOrders) Configure(app *App) {}
+
+type moduleUsers struct{}
+
+func (m *moduleUsers) Requires() []Module {
+	return []Module{&moduleDatabase{}}
+}
+
+func (m *moduleUsers) Configure(app *App) {}
+
+func TestApp_InstallSharedRequirement(t *testing.T) {
+	app := Empty(&AppConfig{Name: "test"})
+	app.Install(&moduleOrders{}, &moduleUsers{})
+
+	if n := len(app.services[service{key: From[*keyedDB](), name: "primary"}]); n != 1 {
+		t.Errorf("Expected shared requirement to be installed once, got %d", n)
+	}
+}
+
+func TestApp_InstallKeyed(t *testing.T) {
+	app := Empty(&AppConfig{Name: "test"})
+	app.Install(moduleNamed{Name: "primary"}, moduleNamed{Name: "replica"}, moduleNamed{Name: "primary"})
+
+	for _, name := range []string{"primary", "replica"} {
+		if n := len(app.services[service{key: From[*keyedDB](), name: name}]); n != 1 {
+			t.Errorf("Expected module %q to be installed once, got %d", name, n)
+		}
+	}
+	if app.modules[0] != "gofast.moduleNamed[primary]" || app.modules[1] != "gofast.moduleNamed[replica]" {
+		t.Errorf("Expected keyed module names, got %v", app.modules)
+	}
+}
+
+type moduleSection struct {
+	DSN string `json:"DSN"`
+}
+
+func (m moduleSection) Path() []string {
+	return []string{"Database"}
+}
+
+func (m moduleSection) Configure(app *App) {
+	RegisterKeyed[*keyedDB](app, "section", func(ctx *BuilderContext) *keyedDB {
+		return &keyedDB{dsn: m.DSN}
+	}, AsSingleton)
+}
+
+func TestApp_InstallValueConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte(`{"Database":{"DSN":"postgres://section"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	app := newQuietApp(t, WithConfigFiles(file))
+	app.Install(moduleSection{})
+
+	if db := MustGetKeyed[*keyedDB](newTestContext(app), "section"); db.dsn != "postgres://section" {
+		t.Errorf("Expected value module config section to be loaded, got %q", db.dsn)
+	}
+}
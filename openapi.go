@@ -0,0 +1,424 @@
+package gofast
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const OpenAPIVersion = "3.1.0"
+
+type Route struct {
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	Request     reflect.Type
+	Response    reflect.Type
+	Status      int
+	Errors      []int
+}
+
+type DescribedController interface {
+	Describe() []Route
+}
+
+/*
+** Router
+ */
+
+type Router struct {
+	*http.ServeMux
+	routes []*Route
+}
+
+func NewRouter() *Router {
+	return &Router{ServeMux: http.NewServeMux()}
+}
+
+func (r *Router) Handle(pattern string, handler http.Handler) *Route {
+	r.ServeMux.Handle(pattern, handler)
+	route := &Route{}
+	route.Method, route.Path = split_pattern(pattern)
+	if th, ok := handler.(*typedHandler); ok {
+		route.Request = th.request
+		route.Response = th.response
+		route.Errors = []int{http.StatusBadRequest, http.StatusInternalServerError}
+	}
+	r.routes = append(r.routes, route)
+	return route
+}
+
+func (r *Router) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) *Route {
+	return r.Handle(pattern, http.HandlerFunc(handler))
+}
+
+func (r *Router) Describe() []Route {
+	routes := make([]Route, 0, len(r.routes))
+	for _, route := range r.routes {
+		routes = append(routes, *route)
+	}
+	return routes
+}
+
+func split_pattern(pattern string) (string, string) {
+	method, path, found := strings.Cut(pattern, " ")
+	if !found {
+		method, path = "", pattern
+	}
+	path = strings.TrimSpace(path)
+	if i := strings.Index(path, "/"); i > 0 {
+		path = path[i:]
+	}
+	return method, path
+}
+
+/*
+** Document
+ */
+
+var wildcard = regexp.MustCompile(`\{([^}]*?)(\.\.\.)?\}`)
+
+type openapi struct {
+	schemas map[string]any
+	names   map[reflect.Type]string
+}
+
+func describe(ctrl Controller) ([]Route, bool) {
+	if dc, ok := ctrl.(DescribedController); ok {
+		return dc.Describe(), true
+	}
+	if dc, ok := ctrl.Routes().(DescribedController); ok {
+		return dc.Describe(), true
+	}
+	return nil, false
+}
+
+func new_openapi(ctrls []Controller, cfg OpenAPIConfig) map[string]any {
+	doc := &openapi{
+		schemas: map[string]any{},
+		names: map[reflect.Type]string{
+			From[problem]():     "Problem",
+			From[fieldDetail](): "FieldError",
+		},
+	}
+	paths := map[string]map[string]any{}
+	tags := []map[string]any{}
+	for _, ctrl := range ctrls {
+		prefix := strings.Trim(ctrl.Prefix(), "/")
+		tag := prefix
+		if tag == "" {
+			tag = "root"
+		}
+		tags = append(tags, map[string]any{"name": tag, "description": fmt.Sprintf("%T", ctrl)})
+		routes, ok := describe(ctrl)
+		if !ok {
+			path := "/" + prefix
+			if paths[path] == nil {
+				paths[path] = map[string]any{}
+			}
+			paths[path]["summary"] = fmt.Sprintf("%T (routes not described)", ctrl)
+			continue
+		}
+		for _, route := range routes {
+			path, params := doc.path(prefix, route.Path)
+			if paths[path] == nil {
+				paths[path] = map[string]any{}
+			}
+			method := strings.ToLower(route.Method)
+			if method == "" {
+				method = "get"
+			}
+			paths[path][method] = doc.operation(tag, route, params)
+		}
+	}
+	return map[string]any{
+		"openapi": OpenAPIVersion,
+		"info": map[string]any{
+			"title":   cfg.Title,
+			"version": cfg.Version,
+		},
+		"tags":  tags,
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": doc.schemas,
+		},
+	}
+}
+
+func (d *openapi) path(prefix string, path string) (string, []string) {
+	path = strings.TrimSuffix(path, "{$}")
+	var params []string
+	path = wildcard.ReplaceAllStringFunc(path, func(m string) string {
+		name := wildcard.FindStringSubmatch(m)[1]
+		params = append(params, name)
+		return "{" + name + "}"
+	})
+	full := "/" + prefix
+	if path = strings.Trim(path, "/"); path != "" {
+		full = strings.TrimSuffix(full, "/") + "/" + path
+	}
+	return full, params
+}
+
+func (d *openapi) operation(tag string, route Route, params []string) map[string]any {
+	op := map[string]any{
+		"tags": []string{tag},
+	}
+	if route.Summary != "" {
+		op["summary"] = route.Summary
+	}
+	if route.Description != "" {
+		op["description"] = route.Description
+	}
+
+	parameters := []map[string]any{}
+	body := map[string]any{}
+	form := map[string]any{}
+	var bodyRequired, formRequired []string
+	declared := map[string]bool{}
+	if t := deref(route.Request); t != nil && t.Kind() == reflect.Struct {
+		for _, f := range schema_fields(t) {
+			schema := d.schema(f.Type)
+			constrain(schema, f)
+			required := is_required(f)
+			bound := false
+			for _, in := range []string{BindPath, BindQuery, BindHeader} {
+				if name, ok := f.Tag.Lookup(in); ok && name != "-" {
+					bound = true
+					declared[name] = in == BindPath
+					parameters = append(parameters, map[string]any{"name": name, "in": in, "required": required || in == BindPath, "schema": schema})
+				}
+			}
+			if name, ok := f.Tag.Lookup(BindForm); ok && name != "-" {
+				form[name] = schema
+				if required {
+					formRequired = append(formRequired, name)
+				}
+				continue
+			}
+			if !bound && f.Tag.Get("json") != "-" {
+				name := field_name(f)
+				body[name] = schema
+				if required {
+					bodyRequired = append(bodyRequired, name)
+				}
+			}
+		}
+	}
+	for _, name := range params {
+		if !declared[name] {
+			parameters = append(parameters, map[string]any{"name": name, "in": "path", "required": true, "schema": map[string]any{"type": "string"}})
+		}
+	}
+	if len(parameters) > 0 {
+		op["parameters"] = parameters
+	}
+
+	content := map[string]any{}
+	if len(body) > 0 {
+		content["application/json"] = map[string]any{"schema": object(body, bodyRequired)}
+	}
+	if len(form) > 0 {
+		content["application/x-www-form-urlencoded"] = map[string]any{"schema": object(form, formRequired)}
+	}
+	if len(content) > 0 && route.Method != http.MethodGet && route.Method != http.MethodHead {
+		op["requestBody"] = map[string]any{"content": content}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	response := map[string]any{"description": http.StatusText(status)}
+	if route.Response != nil {
+		response["content"] = map[string]any{"application/json": map[string]any{"schema": d.schema(route.Response)}}
+	}
+	responses := map[string]any{strconv.Itoa(status): response}
+	problems := map[string]any{"application/problem+json": map[string]any{"schema": d.schema(From[problem]())}}
+	for _, code := range route.Errors {
+		responses[strconv.Itoa(code)] = map[string]any{"description": http.StatusText(code), "content": problems}
+	}
+	responses["default"] = map[string]any{"description": "Error", "content": problems}
+	op["responses"] = responses
+	return op
+}
+
+/*
+** JSON Schema
+ */
+
+var timeType = From[time.Time]()
+
+func (d *openapi) schema(t reflect.Type) map[string]any {
+	t = deref(t)
+	if t == nil {
+		return map[string]any{}
+	}
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case durationType:
+		return map[string]any{"type": "integer", "format": "int64"}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case reflect.Int, reflect.Int64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32:
+		return map[string]any{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]any{"type": "number", "format": "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": d.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": d.schema(t.Elem())}
+	case reflect.Struct:
+		return map[string]any{"$ref": "#/components/schemas/" + d.component(t)}
+	default:
+		return map[string]any{}
+	}
+}
+
+func (d *openapi) component(t reflect.Type) string {
+	name, ok := d.names[t]
+	if !ok {
+		name = schema_name(t)
+		taken := slices.Collect(maps.Values(d.names))
+		for i := 2; slices.Contains(taken, name); i++ {
+			name = fmt.Sprintf("%s%d", schema_name(t), i)
+		}
+		d.names[t] = name
+	}
+	if _, built := d.schemas[name]; built {
+		return name
+	}
+	// placeholder for recursive types
+	d.schemas[name] = map[string]any{}
+	properties := map[string]any{}
+	var required []string
+	for _, f := range schema_fields(t) {
+		json, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if json == "-" {
+			continue
+		}
+		schema := d.schema(f.Type)
+		constrain(schema, f)
+		properties[field_name(f)] = schema
+		if is_required(f) || (f.Type.Kind() != reflect.Pointer && !strings.Contains(opts, "omitempty")) {
+			required = append(required, field_name(f))
+		}
+	}
+	d.schemas[name] = object(properties, required)
+	return name
+}
+
+func is_required(f reflect.StructField) bool {
+	return slices.Contains(strings.Split(f.Tag.Get("validate"), ","), "required")
+}
+
+func schema_name(t reflect.Type) string {
+	name := t.Name()
+	if name == "" {
+		return "Object"
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.', r == '-':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func schema_fields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if f.Anonymous && deref(f.Type).Kind() == reflect.Struct && f.Tag.Get("json") == "" {
+			fields = append(fields, schema_fields(deref(f.Type))...)
+			continue
+		}
+		if f.IsExported() {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func constrain(schema map[string]any, f reflect.StructField) {
+	if _, ref := schema["$ref"]; ref {
+		return
+	}
+	for _, part := range strings.Split(f.Tag.Get("validate"), ",") {
+		name, param, _ := strings.Cut(part, "=")
+		switch name {
+		case "email":
+			schema["format"] = "email"
+		case "oneof":
+			schema["enum"] = enum(schema["type"], strings.Fields(param))
+		case "min", "max":
+			if n, err := strconv.ParseFloat(param, 64); err == nil {
+				schema[limit_keyword(schema["type"], name)] = n
+			}
+		}
+	}
+}
+
+func enum(kind any, options []string) []any {
+	values := make([]any, 0, len(options))
+	for _, option := range options {
+		if kind == "integer" || kind == "number" {
+			if n, err := strconv.ParseFloat(option, 64); err == nil {
+				values = append(values, n)
+				continue
+			}
+		}
+		values = append(values, option)
+	}
+	return values
+}
+
+func limit_keyword(kind any, rule string) string {
+	switch kind {
+	case "string":
+		return rule + "Length"
+	case "array":
+		return rule + "Items"
+	case "object":
+		return rule + "Properties"
+	default:
+		return rule + "imum"
+	}
+}
+
+func object(properties map[string]any, required []string) map[string]any {
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
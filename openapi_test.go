@@ -0,0 +1,138 @@
+package gofast
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type openapiItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type openapiRequest struct {
+	ID    int    `path:"id"`
+	Trace string `header:"X-Trace"`
+	Name  string `json:"name" validate:"required,max=64"`
+	Kind  string `json:"kind" validate:"oneof=a b"`
+}
+
+type openapiController struct{}
+
+func (c *openapiController) Prefix() string {
+	return "items"
+}
+
+func (c *openapiController) Routes() http.Handler {
+	router := NewRouter()
+	router.Handle("PUT /{id}", Handle(func(ctx *BuilderContext, req openapiRequest) (*openapiItem, error) {
+		return &openapiItem{ID: req.ID, Name: req.Name}, nil
+	})).Summary = "Update item"
+	return router
+}
+
+func TestOpenAPIController(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte(`{"OpenAPI":{"Prefix":"docs/openapi","Title":"items","Version":"2.0.0"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	app := newQuietApp(t, WithConfigFiles(file))
+	Add(app, func(*BuilderContext) *openapiController {
+		return &openapiController{}
+	})
+	Add(app, OpenAPIControllerBuilder())
+
+	w := httptest.NewRecorder()
+	app.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/openapi", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Info    struct {
+			Title   string `json:"title"`
+			Version string `json:"version"`
+		} `json:"info"`
+		Paths map[string]map[string]struct {
+			Summary    string `json:"summary"`
+			Parameters []struct {
+				Name string `json:"name"`
+				In   string `json:"in"`
+			} `json:"parameters"`
+			RequestBody struct {
+				Content map[string]struct {
+					Schema struct {
+						Properties map[string]map[string]any `json:"properties"`
+						Required   []string                  `json:"required"`
+					} `json:"schema"`
+				} `json:"content"`
+			} `json:"requestBody"`
+			Responses map[string]struct {
+				Content map[string]struct {
+					Schema map[string]any `json:"schema"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.OpenAPI != OpenAPIVersion || doc.Info.Title != "items" || doc.Info.Version != "2.0.0" {
+		t.Errorf("Unexpected document header: %+v", doc)
+	}
+	for _, path := range []string{"/items/{id}", "/health", "/docs/openapi"} {
+		if _, ok := doc.Paths[path]; !ok {
+			t.Errorf("Expected path %q, got %v", path, doc.Paths)
+		}
+	}
+
+	op := doc.Paths["/items/{id}"]["put"]
+	if op.Summary != "Update item" || len(op.Parameters) != 2 || op.Parameters[0].In != "path" || op.Parameters[1].In != "header" {
+		t.Errorf("Unexpected operation: %+v", op)
+	}
+	body := op.RequestBody.Content["application/json"].Schema
+	if len(body.Properties) != 2 || body.Properties["name"]["maxLength"] != 64.0 || len(body.Required) != 1 {
+		t.Errorf("Unexpected request body: %+v", body)
+	}
+	if ref := op.Responses["200"].Content["application/json"].Schema["$ref"]; ref != "#/components/schemas/openapiItem" {
+		t.Errorf("Unexpected response schema: %v", ref)
+	}
+	if ref := op.Responses["400"].Content["application/problem+json"].Schema["$ref"]; ref != "#/components/schemas/Problem" {
+		t.Errorf("Unexpected error schema: %v", ref)
+	}
+	for _, name := range []string{"openapiItem", "Problem", "FieldError"} {
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("Expected component %q", name)
+		}
+	}
+}
+
+func TestOpenAPIController_ReadsConfigOnce(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte(`{"OpenAPI":{"Prefix":"docs"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	app := newQuietApp(t, WithConfigFiles(file))
+	Add(app, OpenAPIControllerBuilder())
+	handler := app.Handler()
+
+	for i := range 2 {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Request %d: expected status %d, got %d", i, http.StatusOK, w.Code)
+		}
+		if err := os.WriteFile(file, []byte(`{"OpenAPI":`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package gofast
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"time"
+)
+
+type Option func(*options)
+
+type options struct {
+	settings    Settings
+	config      ConfigSettings
+	files       ConfigHelper
+	environment EnvironmentHelper
+	shutdown    time.Duration
+	defaults    bool
+}
+
+func WithDebug(debug bool) Option {
+	return func(o *options) {
+		o.settings.DEBUG = debug
+	}
+}
+
+func WithConfigFiles(files ...string) Option {
+	return func(o *options) {
+		o.files.files = slices.Clone(files)
+		o.defaults = false
+	}
+}
+
+func WithEnv(env bool) Option {
+	return func(o *options) {
+		o.files.env = env
+	}
+}
+
+func WithEnvPrefix(prefix string) Option {
+	return func(o *options) {
+		o.config.ENV_PREFIX = prefix
+	}
+}
+
+func WithApplicationPath(keys ...string) Option {
+	return func(o *options) {
+		o.config.APPLICATION_PATH = slices.Clone(keys)
+	}
+}
+
+func WithEnvironment(environment string) Option {
+	return func(o *options) {
+		o.environment.value = environment
+	}
+}
+
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.shutdown = timeout
+	}
+}
+
+func default_options() *options {
+	return &options{
+		settings: *SETTINGS,
+		config: ConfigSettings{
+			APPLICATION_PATH: slices.Clone(CONFIG.APPLICATION_PATH),
+			ENV_PREFIX:       CONFIG.ENV_PREFIX,
+		},
+		files: ConfigHelper{
+			files: slices.Clone(ConfigFiles.files),
+			env:   ConfigFiles.env,
+		},
+		environment: *Environment,
+		shutdown:    ShutdownTimeout,
+	}
+}
+
+func new_options(standard bool, opts []Option) *options {
+	o := default_options()
+	if standard {
+		o.files.env = true
+		o.defaults = true
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if standard && o.environment.value == "" {
+		o.environment.read(o.config.ENV_PREFIX)
+	}
+	if o.defaults {
+		o.files.Add("config.json")
+		o.files.Add("config." + o.environment.value + ".json")
+	}
+	return o
+}
+
+func (o *options) read(v any, keys ...string) error {
+	var errs []error
+	for _, file := range o.files.files {
+		data, err := os.ReadFile(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err == nil {
+			err = get_nested_config(data, &v, keys...)
+		}
+		if err != nil && !errors.Is(err, errConfigKeyNotFound) {
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+		}
+	}
+	if o.files.env {
+		data, err := read_env(o.config.ENV_PREFIX)
+		if err == nil {
+			err = get_nested_config(data, &v, keys...)
+		}
+		if err != nil && !errors.Is(err, errConfigKeyNotFound) {
+			errs = append(errs, fmt.Errorf("environment: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,271 @@
+package gofast
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type ErrorRenderer interface {
+	Render(w http.ResponseWriter, r *http.Request, err *HTTPError)
+}
+
+/*
+** HTTPError
+ */
+
+type HTTPError struct {
+	Status int
+	Type   string
+	Title  string
+	Detail string
+	Errors []*FieldError
+	Err    error
+}
+
+func NewHTTPError(status int, detail string) *HTTPError {
+	return &HTTPError{Status: status, Detail: detail}
+}
+
+func (e *HTTPError) Error() string {
+	if e.Detail == "" {
+		return e.title()
+	}
+	return e.title() + ": " + e.Detail
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+func (e *HTTPError) StatusCode() int {
+	return e.Status
+}
+
+func (e *HTTPError) title() string {
+	if e.Title != "" {
+		return e.Title
+	}
+	return http.StatusText(e.Status)
+}
+
+func AsHTTPError(err error) *HTTPError {
+	var he *HTTPError
+	var coder StatusCoder
+	var bind *BindError
+	var validation *ValidationError
+	var limit *http.MaxBytesError
+	switch {
+	case errors.As(err, &he):
+		return he
+	case errors.As(err, &validation):
+		return &HTTPError{Status: http.StatusBadRequest, Detail: "validation failed", Errors: validation.Fields, Err: err}
+	case errors.As(err, &limit):
+		return &HTTPError{Status: http.StatusRequestEntityTooLarge, Detail: limit.Error(), Err: err}
+	case errors.As(err, &bind):
+		return &HTTPError{Status: http.StatusBadRequest, Detail: bind.Error(), Err: err}
+	case errors.As(err, &coder):
+		return &HTTPError{Status: coder.StatusCode(), Detail: err.Error(), Err: err}
+	default:
+		return &HTTPError{Status: http.StatusInternalServerError, Err: err}
+	}
+}
+
+func renderer_of(ctx *BuilderContext) ErrorRenderer {
+	if ctx.app != nil && ctx.app.has(service{key: From[ErrorRenderer]()}) {
+		return MustGet[ErrorRenderer](ctx, Singleton)
+	}
+	return NegotiatingRendererBuilder()(ctx)
+}
+
+/*
+** ProblemRenderer
+ */
+
+type ProblemRenderer struct{}
+
+type problem struct {
+	Type      string        `json:"type"`
+	Title     string        `json:"title"`
+	Status    int           `json:"status"`
+	Detail    string        `json:"detail,omitempty"`
+	Instance  string        `json:"instance,omitempty"`
+	RequestID any           `json:"requestId,omitempty"`
+	Errors    []fieldDetail `json:"errors,omitempty"`
+}
+
+type fieldDetail struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func ProblemRendererBuilder() Builder[*ProblemRenderer] {
+	return func(*BuilderContext) *ProblemRenderer {
+		return &ProblemRenderer{}
+	}
+}
+
+func (p *ProblemRenderer) Render(w http.ResponseWriter, r *http.Request, err *HTTPError) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Del("Content-Length")
+	w.WriteHeader(err.Status)
+	json.NewEncoder(w).Encode(new_problem(r, err))
+}
+
+func new_problem(r *http.Request, err *HTTPError) problem {
+	p := problem{
+		Type:      err.Type,
+		Title:     err.title(),
+		Status:    err.Status,
+		Detail:    err.Detail,
+		Instance:  r.URL.Path,
+		RequestID: r.Context().Value(CtxRequestId),
+	}
+	// controllers see the path without their prefix
+	if path, ok := r.Context().Value(CtxPath).(string); ok {
+		p.Instance = path
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	for _, f := range err.Errors {
+		p.Errors = append(p.Errors, fieldDetail{Field: f.Field, Rule: f.Rule, Message: f.Message})
+	}
+	return p
+}
+
+/*
+** HTMLRenderer
+ */
+
+type HTMLRenderer struct {
+	template *template.Template
+}
+
+var htmlProblem = template.Must(template.New("problem").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Status}} {{.Title}}</title></head>
+<body>
+<h1>{{.Status}} {{.Title}}</h1>
+{{if .Detail}}<p>{{.Detail}}</p>{{end}}
+{{if .Errors}}<ul>{{range .Errors}}<li>{{.Field}} {{.Message}}</li>{{end}}</ul>{{end}}
+{{if .RequestID}}<p><small>Request ID: {{.RequestID}}</small></p>{{end}}
+</body>
+</html>
+`))
+
+func HTMLRendererBuilder() Builder[*HTMLRenderer] {
+	return func(*BuilderContext) *HTMLRenderer {
+		return &HTMLRenderer{template: htmlProblem}
+	}
+}
+
+func (h *HTMLRenderer) Render(w http.ResponseWriter, r *http.Request, err *HTTPError) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Del("Content-Length")
+	w.WriteHeader(err.Status)
+	h.template.Execute(w, new_problem(r, err))
+}
+
+/*
+** NegotiatingRenderer
+ */
+
+type NegotiatingRenderer struct {
+	Problem ErrorRenderer
+	HTML    ErrorRenderer
+}
+
+func NegotiatingRendererBuilder() Builder[*NegotiatingRenderer] {
+	return func(*BuilderContext) *NegotiatingRenderer {
+		return &NegotiatingRenderer{Problem: &ProblemRenderer{}, HTML: &HTMLRenderer{template: htmlProblem}}
+	}
+}
+
+func (n *NegotiatingRenderer) Render(w http.ResponseWriter, r *http.Request, err *HTTPError) {
+	if prefers_html(r) {
+		n.HTML.Render(w, r, err)
+		return
+	}
+	n.Problem.Render(w, r, err)
+}
+
+// problem+json wins ties, html only when the client ranks it higher
+func prefers_html(r *http.Request) bool {
+	var html, json float64
+	for part := range strings.SplitSeq(r.Header.Get("Accept"), ",") {
+		media, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			q, _ = strconv.ParseFloat(v, 64)
+		}
+		switch media {
+		case "text/html", "application/xhtml+xml":
+			html = max(html, q)
+		case "application/json", "application/problem+json":
+			json = max(json, q)
+		}
+	}
+	return html > json
+}
+
+/*
+** Unmatched routes
+ */
+
+type matcher interface {
+	http.Handler
+	Handler(r *http.Request) (http.Handler, string)
+}
+
+type unmatched struct {
+	mux      matcher
+	renderer ErrorRenderer
+}
+
+func render_unmatched(h http.Handler, renderer ErrorRenderer) http.Handler {
+	if mux, ok := h.(matcher); ok {
+		return &unmatched{mux: mux, renderer: renderer}
+	}
+	return h
+}
+
+func (u *unmatched) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, pattern := u.mux.Handler(r); pattern != "" {
+		u.mux.ServeHTTP(w, r)
+		return
+	}
+	sw := &statusWriter{ResponseWriter: w}
+	u.mux.ServeHTTP(sw, r)
+	if sw.status != 0 {
+		u.renderer.Render(w, r, NewHTTPError(sw.status, ""))
+	}
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if code == http.StatusNotFound || code == http.StatusMethodNotAllowed {
+		w.status = code
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status != 0 {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,108 @@
+package gofast
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type problemController struct{}
+
+func (c *problemController) Prefix() string {
+	return "problem"
+}
+
+func (c *problemController) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	mux.HandleFunc("GET /slow", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	mux.HandleFunc("POST /upload", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
+
+func newProblemApp(t *testing.T) http.Handler {
+	app := newQuietApp(t)
+	Add(app, func(*BuilderContext) *problemController {
+		return &problemController{}
+	})
+	RemoveImpl[Middleware, *TimeoutMiddleware](app)
+	Use(app, func(*BuilderContext) *TimeoutMiddleware {
+		return &TimeoutMiddleware{Timeout: 10 * time.Millisecond, renderer: &ProblemRenderer{}}
+	}, AsSingleton)
+	return app.Handler()
+}
+
+func TestProblemRenderer(t *testing.T) {
+	handler := newProblemApp(t)
+
+	for _, tc := range []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{http.MethodGet, "/missing", "", http.StatusNotFound},
+		{http.MethodGet, "/problem/missing", "", http.StatusNotFound},
+		{http.MethodPost, "/problem/panic", "", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/problem/panic", "", http.StatusInternalServerError},
+		{http.MethodGet, "/problem/slow", "", http.StatusServiceUnavailable},
+		{http.MethodPost, "/problem/upload", strings.Repeat("x", 2<<20), http.StatusRequestEntityTooLarge},
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body)))
+
+		var p problem
+		if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+			t.Errorf("%s %s: expected problem body, got %q", tc.method, tc.path, w.Body.String())
+			continue
+		}
+		if w.Code != tc.status || p.Status != tc.status || p.RequestID == nil || p.Instance != tc.path {
+			t.Errorf("%s %s: expected status %d with request ID and instance, got %d %+v", tc.method, tc.path, tc.status, w.Code, p)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+			t.Errorf("%s %s: unexpected content type %q", tc.method, tc.path, ct)
+		}
+	}
+}
+
+func TestHTMLRenderer(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	HTMLRendererBuilder()(nil).Render(w, r, NewHTTPError(http.StatusNotFound, "<missing>"))
+
+	if w.Code != http.StatusNotFound || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("Unexpected response %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Body.String(), "&lt;missing&gt;") {
+		t.Errorf("Expected escaped detail, got %q", w.Body.String())
+	}
+}
+
+func TestNegotiatingRenderer(t *testing.T) {
+	handler := newProblemApp(t)
+
+	for accept, want := range map[string]string{
+		"":                                  "application/problem+json",
+		"*/*":                               "application/problem+json",
+		"application/json, text/html;q=0.5": "application/problem+json",
+		"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8": "text/html; charset=utf-8",
+	} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/missing", nil)
+		r.Header.Set("Accept", accept)
+		handler.ServeHTTP(w, r)
+
+		if w.Code != http.StatusNotFound || w.Header().Get("Content-Type") != want {
+			t.Errorf("Accept %q: expected 404 %q, got %d %q", accept, want, w.Code, w.Header().Get("Content-Type"))
+		}
+	}
+}
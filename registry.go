@@ -0,0 +1,182 @@
+package gofast
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/ugozlave/cargo"
+)
+
+type service struct {
+	key  reflect.Type
+	name string
+}
+
+func (s service) String() string {
+	if s.name == "" {
+		return s.key.String()
+	}
+	return fmt.Sprintf("%v[%s]", s.key, s.name)
+}
+
+type RegisterOption interface {
+	apply(*registration)
+}
+
+type lifetimeOption Lifetime
+
+const (
+	AsSingleton = lifetimeOption(Singleton)
+	AsScoped    = lifetimeOption(Scoped)
+	AsTransient = lifetimeOption(Transient)
+)
+
+func (o lifetimeOption) apply(r *registration) {
+	r.lifetime = Lifetime(o)
+	r.declared = true
+}
+
+func (s service) error(ctx *BuilderContext, lt Lifetime, err error) *ResolveError {
+	return &ResolveError{Type: s.key, Name: s.name, Scope: ctx.scope(lt), Err: err}
+}
+
+type registration struct {
+	id       string
+	service  service
+	value    reflect.Type
+	build    func(*BuilderContext) any
+	lifetime Lifetime
+	declared bool
+	module   string
+}
+
+func (app *App) register(svc service, value reflect.Type, build func(*BuilderContext) any, opts ...RegisterOption) {
+	ctn := app.container
+	app.sequence++
+	reg := &registration{
+		id:      fmt.Sprintf("%v#%d", svc, app.sequence),
+		service: svc,
+		value:   value,
+		build:   build,
+		module:  app.module,
+	}
+	for _, opt := range opts {
+		opt.apply(reg)
+	}
+	if app.services == nil {
+		app.services = make(map[service][]*registration)
+	}
+	app.registrations = append(app.registrations, reg)
+	app.services[svc] = append(app.services[svc], reg)
+	ctn.Register(reg.id, value.String(), func(ctx cargo.BuilderContext) any {
+		bctx, ok := ctx.(*BuilderContext)
+		if !ok {
+			bctx = NewBuilderContext(ctx, ctn)
+		}
+		return reg.create(bctx)
+	})
+}
+
+func (app *App) remove(svc service, match func(*registration) bool) {
+	del := func(reg *registration) bool {
+		return reg.service == svc && match(reg)
+	}
+	app.registrations = slices.DeleteFunc(app.registrations, del)
+	if registrations := slices.DeleteFunc(app.services[svc], del); len(registrations) > 0 {
+		app.services[svc] = registrations
+	} else {
+		delete(app.services, svc)
+	}
+}
+
+func (app *App) decorate(svc service, decorator func(*BuilderContext, any) any) {
+	if app.decorators == nil {
+		app.decorators = make(map[service][]func(*BuilderContext, any) any)
+	}
+	app.decorators[svc] = append(app.decorators[svc], decorator)
+}
+
+func (r *registration) String() string {
+	return fmt.Sprintf("%v (%v)", r.service, r.value)
+}
+
+func (r *registration) resolve_lifetime(ctx *BuilderContext, lts []Lifetime) Lifetime {
+	switch {
+	case len(lts) > 0 && r.declared && lts[0] != r.lifetime:
+		if _, warned := ctx.app.conflicts.LoadOrStore(conflict{r, lts[0]}, true); !warned {
+			ctx.logger().Wrn(fmt.Sprintf("lifetime conflict: %v is registered as %v but requested as %v", r, r.lifetime, lts[0]))
+		}
+		return r.lifetime
+	case len(lts) > 0:
+		return lts[0]
+	case r.declared:
+		return r.lifetime
+	default:
+		return ctx.lifetime()
+	}
+}
+
+type conflict struct {
+	registration *registration
+	lifetime     Lifetime
+}
+
+func (r *registration) create(ctx *BuilderContext) any {
+	instance := r.build(ctx)
+	ctx.track(instance)
+	for _, decorator := range ctx.app.decorators[r.service] {
+		instance = decorator(ctx, instance)
+	}
+	return instance
+}
+
+func (r *registration) instance(ctx *BuilderContext, lt Lifetime) any {
+	ctn := ctx.container
+	if scope := ctx.scope(lt); scope != "" {
+		return ctn.MustGet(r.id, scope, ctx)
+	}
+	return ctn.MustBuild(r.id, ctx)
+}
+
+func (r *registration) validate(ctx *BuilderContext) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("%v: %v", r, rec)
+		}
+	}()
+	var instance any
+	if r.declared && r.lifetime == Singleton {
+		instance = r.instance(ctx.enter(r.service, Singleton), Singleton)
+	} else {
+		instance = r.create(ctx.enter(r.service, Scoped))
+	}
+	if is_nil(instance) {
+		return fmt.Errorf("%v: builder returned nil", r)
+	}
+	// typed handlers would otherwise only fail on their first request
+	if ctrl, ok := instance.(Controller); ok {
+		routes, _ := describe(ctrl)
+		var errs []error
+		for _, route := range routes {
+			if err := validator_of(ctx).verify(route.Request, map[reflect.Type]bool{}); err != nil {
+				errs = append(errs, fmt.Errorf("%v: %s %s: %w", r, route.Method, route.Path, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+func is_nil(v any) bool {
+	if v == nil {
+		return true
+	}
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return value.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package gofast
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const ScopeRoutingId = "routing"
+
+func (app *App) compile(ctx context.Context) http.Handler {
+	ctn := app.container
+
+	ctx = context.WithValue(ctx, CtxRequestId, ScopeRoutingId)
+	bctx := NewBuilderContext(ctx, ctn)
+
+	scope := fmt.Sprintf(ScopeRequestKeyFormat, ScopeRoutingId)
+	create_scope(bctx, scope)
+	defer delete_scope(bctx, scope)
+
+	return app.middlewares(bctx, app.controllers(bctx))
+}
+
+func (app *App) controllers(ctx *BuilderContext) http.Handler {
+	mux := http.NewServeMux()
+	renderer := renderer_of(ctx)
+	svc := service{key: From[Controller]()}
+	for _, reg := range app.services[svc] {
+		lt := reg.resolve_lifetime(ctx, nil)
+		ctrl := reg.instance(ctx.enter(svc, lt), lt).(Controller)
+		prefix := "/" + strings.Trim(ctrl.Prefix(), "/")
+		var handler http.Handler
+		if lt == Singleton {
+			handler = controller_routes(ctrl, renderer)
+		} else {
+			handler = &lazyController{service: svc, registration: reg, lifetime: lt, renderer: renderer}
+		}
+		handler = strip_prefix(prefix, handler)
+		mux.Handle(prefix, handler)
+		if prefix != "/" {
+			mux.Handle(prefix+"/", handler)
+		}
+	}
+	return render_unmatched(mux, renderer)
+}
+
+func (app *App) middlewares(ctx *BuilderContext, next http.Handler) http.Handler {
+	svc := service{key: From[Middleware]()}
+	for _, reg := range slices.Backward(app.services[svc]) {
+		lt := reg.resolve_lifetime(ctx, nil)
+		if lt == Singleton {
+			next = reg.instance(ctx.enter(svc, lt), lt).(Middleware).Handle(next)
+		} else {
+			next = &lazyMiddleware{service: svc, registration: reg, lifetime: lt, next: next}
+		}
+	}
+	return next
+}
+
+/*
+** Lazy handlers
+ */
+
+type lazyController struct {
+	service      service
+	registration *registration
+	lifetime     Lifetime
+	renderer     ErrorRenderer
+}
+
+func (h *lazyController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := FromContext(r.Context())
+	ctrl := h.registration.instance(ctx.enter(h.service, h.lifetime), h.lifetime).(Controller)
+	controller_routes(ctrl, h.renderer).ServeHTTP(w, r)
+}
+
+type lazyMiddleware struct {
+	service      service
+	registration *registration
+	lifetime     Lifetime
+	next         http.Handler
+}
+
+func (h *lazyMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := FromContext(r.Context())
+	mid := h.registration.instance(ctx.enter(h.service, h.lifetime), h.lifetime).(Middleware)
+	mid.Handle(h.next).ServeHTTP(w, r)
+}
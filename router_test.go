@@ -0,0 +1,193 @@
+package gofast
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type routerController struct{}
+
+func (c *routerController) Prefix() string {
+	return "items"
+}
+
+func (c *routerController) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.PathValue("id")))
+	})
+	return mux
+}
+
+type routerMiddleware struct{}
+
+func (m *routerMiddleware) Handle(next http.Handler) http.Handler {
+	return next
+}
+
+func newRouterApp(tb testing.TB, controllers, middlewares *int, opts ...RegisterOption) *App {
+	app := newQuietApp(tb)
+	Add(app, func(ctx *BuilderContext) *routerController {
+		*controllers++
+		return &routerController{}
+	}, opts...)
+	Use(app, func(ctx *BuilderContext) *routerMiddleware {
+		*middlewares++
+		return &routerMiddleware{}
+	}, AsSingleton)
+	return app
+}
+
+func TestApp_HandlerPrecompiled(t *testing.T) {
+	var controllers, middlewares int
+	app := newRouterApp(t, &controllers, &middlewares)
+
+	handler := app.Handler()
+	controllers = 0
+
+	for i := range 3 {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/items/%d", i), nil))
+		if w.Code != http.StatusOK || w.Body.String() != fmt.Sprint(i) {
+			t.Fatalf("Unexpected response %d %q", w.Code, w.Body.String())
+		}
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health/", nil))
+
+	if middlewares != 1 {
+		t.Errorf("Expected singleton middleware to be built once, got %d", middlewares)
+	}
+	if controllers != 3 {
+		t.Errorf("Expected scoped controller to be built for matched requests only, got %d", controllers)
+	}
+}
+
+// perRequestInjector approximates the previous design, wiring every controller and
+// middleware per request, but on top of the current resolver rather than the old one.
+type perRequestInjector struct{ *HttpInjector }
+
+func (inj perRequestInjector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := inj.gen.Next()
+	ctx := NewBuilderContext(context.WithValue(inj.app.context(r.Context()), CtxRequestId, id), inj.ctn)
+	scope := fmt.Sprintf(ScopeRequestKeyFormat, id)
+	create_scope(ctx, scope)
+	defer delete_scope(ctx, scope)
+	mux := http.NewServeMux()
+	renderer := renderer_of(ctx)
+	for _, ctrl := range All[Controller](ctx) {
+		prefix := strings.Trim(ctrl.Prefix(), "/")
+		handler := strip_prefix("/"+prefix, controller_routes(ctrl, renderer))
+		mux.Handle("/"+prefix, handler)
+		mux.Handle("/"+prefix+"/", handler)
+	}
+	var handler http.Handler = render_unmatched(mux, renderer)
+	for _, mid := range slices.Backward(All[Middleware](ctx)) {
+		handler = mid.Handle(handler)
+	}
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+func benchmarkHandler(b *testing.B, handler func(*App) http.Handler, opts ...RegisterOption) {
+	var controllers, middlewares int
+	app := newRouterApp(b, &controllers, &middlewares, opts...)
+
+	h := handler(app)
+	r := httptest.NewRequest(http.MethodGet, "/items/42", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		h.ServeHTTP(httptest.NewRecorder(), r)
+	}
+}
+
+func BenchmarkHttpInjector_PerRequest(b *testing.B) {
+	benchmarkHandler(b, func(app *App) http.Handler {
+		return perRequestInjector{app.Handler().(*HttpInjector)}
+	})
+}
+
+func BenchmarkHttpInjector_Precompiled(b *testing.B) {
+	benchmarkHandler(b, func(app *App) http.Handler {
+		return app.Handler()
+	})
+}
+
+// the default scoped controller still rebuilds its routes on every request
+func BenchmarkHttpInjector_PrecompiledSingleton(b *testing.B) {
+	benchmarkHandler(b, func(app *App) http.Handler {
+		return app.Handler()
+	}, AsSingleton)
+}
+
+type orderMiddleware struct {
+	name  string
+	order *[]string
+}
+
+func (m *orderMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*m.order = append(*m.order, m.name+" "+r.URL.Path)
+		if r.Header.Get("Authorization") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type globalMiddleware struct{ order *[]string }
+
+func (m *globalMiddleware) Handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*m.order = append(*m.order, "global "+r.URL.Path)
+		next.ServeHTTP(w, r)
+	})
+}
+
+type adminController struct {
+	routerController
+	order *[]string
+}
+
+func (c *adminController) Prefix() string {
+	return "admin"
+}
+
+func (c *adminController) ControllerMiddlewares() []Middleware {
+	return []Middleware{&orderMiddleware{name: "auth", order: c.order}}
+}
+
+func TestApp_ControllerMiddlewares(t *testing.T) {
+	var order []string
+	app := newQuietApp(t)
+	Add(app, func(ctx *BuilderContext) *adminController {
+		return &adminController{order: &order}
+	})
+	Use(app, func(ctx *BuilderContext) *globalMiddleware {
+		return &globalMiddleware{order: &order}
+	})
+	handler := app.Handler()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected public route to succeed, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/1", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected controller middleware to reject, got %d", w.Code)
+	}
+
+	want := []string{"global /health/", "global /admin/1", "auth /1"}
+	if fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Errorf("Expected order %v, got %v", want, order)
+	}
+}
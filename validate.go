@@ -0,0 +1,318 @@
+package gofast
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type ValidationRule interface {
+	Name() string
+	Validate(value reflect.Value, param string) error
+}
+
+type FieldError struct {
+	Field   string
+	Rule    string
+	Param   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+type ValidationError struct {
+	Fields []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		messages = append(messages, f.Error())
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+/*
+** Validator
+ */
+
+type Validator struct {
+	rules map[string]ValidationRule
+}
+
+func ValidatorBuilder() Builder[*Validator] {
+	return func(ctx *BuilderContext) *Validator {
+		return NewValidator(All[ValidationRule](ctx)...)
+	}
+}
+
+func NewValidator(rules ...ValidationRule) *Validator {
+	v := &Validator{rules: map[string]ValidationRule{}}
+	for _, rule := range builtinRules {
+		v.rules[rule.Name()] = rule
+	}
+	for _, rule := range rules {
+		v.rules[rule.Name()] = rule
+	}
+	return v
+}
+
+func (v *Validator) Validate(x any) error {
+	value := reflect.ValueOf(x)
+	var errs []*FieldError
+	v.walk(value, "", &errs)
+	if len(errs) > 0 {
+		return &ValidationError{Fields: errs}
+	}
+	return nil
+}
+
+func (v *Validator) walk(value reflect.Value, path string, errs *[]*FieldError) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		t := value.Type()
+		for i := range t.NumField() {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			tag := f.Tag.Get("validate")
+			if tag == "-" {
+				continue
+			}
+			field := join_path(path, field_name(f))
+			if f.Anonymous {
+				field = path
+			}
+			if v.check(value.Field(i), field, tag, errs) {
+				v.walk(value.Field(i), field, errs)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range value.Len() {
+			v.walk(value.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+func (v *Validator) check(value reflect.Value, field string, tag string, errs *[]*FieldError) bool {
+	if tag == "" {
+		return true
+	}
+	for _, part := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(part, "=")
+		switch name {
+		case "":
+			continue
+		case "omitempty":
+			if value.IsZero() {
+				return false
+			}
+			continue
+		}
+		rule, ok := v.rules[name]
+		if !ok {
+			panic(fmt.Sprintf("unknown validation rule %q on %s", name, field))
+		}
+		if err := rule.Validate(value, param); err != nil {
+			*errs = append(*errs, &FieldError{Field: field, Rule: name, Param: param, Message: err.Error()})
+			return false
+		}
+	}
+	return true
+}
+
+func (v *Validator) verify(t reflect.Type, seen map[reflect.Type]bool) error {
+	t = deref(t)
+	if t == nil || seen[t] {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return v.verify(t.Elem(), seen)
+	case reflect.Struct:
+	default:
+		return nil
+	}
+	seen[t] = true
+	var errs []error
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("validate")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		for part := range strings.SplitSeq(tag, ",") {
+			name, _, _ := strings.Cut(part, "=")
+			if _, ok := v.rules[name]; !ok && name != "" && name != "omitempty" {
+				errs = append(errs, fmt.Errorf("unknown validation rule %q on %v.%s", name, t, f.Name))
+			}
+		}
+		errs = append(errs, v.verify(f.Type, seen))
+	}
+	return errors.Join(errs...)
+}
+
+func field_name(f reflect.StructField) string {
+	if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	return f.Name
+}
+
+func join_path(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func validator_of(ctx *BuilderContext) *Validator {
+	if ctx.app != nil && ctx.app.has(service{key: From[*Validator]()}) {
+		return MustGet[*Validator](ctx, Singleton)
+	}
+	return defaultValidator
+}
+
+func validate_config(validator *Validator, v any) {
+	if err := validator.Validate(v); err != nil {
+		panic(fmt.Sprintf("invalid config %T: %v", v, err))
+	}
+}
+
+/*
+** Rules
+ */
+
+type ruleFunc struct {
+	name string
+	fn   func(reflect.Value, string) error
+}
+
+func NewRule(name string, fn func(value reflect.Value, param string) error) ValidationRule {
+	return &ruleFunc{name: name, fn: fn}
+}
+
+func (r *ruleFunc) Name() string {
+	return r.name
+}
+
+func (r *ruleFunc) Validate(value reflect.Value, param string) error {
+	return r.fn(value, param)
+}
+
+var builtinRules = []ValidationRule{
+	NewRule("required", rule_required),
+	NewRule("min", rule_min),
+	NewRule("max", rule_max),
+	NewRule("email", rule_email),
+	NewRule("oneof", rule_oneof),
+}
+
+var defaultValidator = NewValidator()
+
+func rule_required(value reflect.Value, _ string) error {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map:
+		if value.Len() > 0 {
+			return nil
+		}
+	default:
+		if !value.IsZero() {
+			return nil
+		}
+	}
+	return errors.New("is required")
+}
+
+func rule_min(value reflect.Value, param string) error {
+	n, ok, err := measure(value, param)
+	if err != nil || !ok || n.size >= n.limit {
+		return err
+	}
+	return fmt.Errorf("must be at least %s%s", param, n.unit)
+}
+
+func rule_max(value reflect.Value, param string) error {
+	n, ok, err := measure(value, param)
+	if err != nil || !ok || n.size <= n.limit {
+		return err
+	}
+	return fmt.Errorf("must be at most %s%s", param, n.unit)
+}
+
+type measurement struct {
+	size  float64
+	limit float64
+	unit  string
+}
+
+func measure(value reflect.Value, param string) (measurement, bool, error) {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return measurement{}, false, nil
+		}
+		value = value.Elem()
+	}
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return measurement{}, false, fmt.Errorf("invalid limit %q", param)
+	}
+	switch value.Kind() {
+	case reflect.String:
+		return measurement{size: float64(utf8.RuneCountInString(value.String())), limit: limit, unit: " characters"}, true, nil
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return measurement{size: float64(value.Len()), limit: limit, unit: " items"}, true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return measurement{size: float64(value.Int()), limit: limit}, true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return measurement{size: float64(value.Uint()), limit: limit}, true, nil
+	case reflect.Float32, reflect.Float64:
+		return measurement{size: value.Float(), limit: limit}, true, nil
+	}
+	return measurement{}, false, nil
+}
+
+func rule_email(value reflect.Value, _ string) error {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.String {
+		return nil
+	}
+	addr, err := mail.ParseAddress(value.String())
+	if err != nil || addr.Name != "" || addr.Address != value.String() {
+		return errors.New("must be a valid email")
+	}
+	return nil
+}
+
+func rule_oneof(value reflect.Value, param string) error {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	options := strings.Fields(param)
+	if slices.Contains(options, fmt.Sprint(value.Interface())) {
+		return nil
+	}
+	return fmt.Errorf("must be one of [%s]", param)
+}
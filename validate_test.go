@@ -0,0 +1,203 @@
+package gofast
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type validateAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type validateUser struct {
+	Name    string            `json:"name" validate:"required,min=1,max=8"`
+	Email   string            `json:"email" validate:"omitempty,email"`
+	Role    string            `json:"role" validate:"oneof=admin user"`
+	Age     int               `json:"age" validate:"min=18"`
+	Tags    []string          `json:"tags" validate:"max=2"`
+	Address validateAddress   `json:"address"`
+	Others  []validateAddress `json:"others"`
+	Code    string            `json:"code" validate:"even"`
+}
+
+func TestValidator_Validate(t *testing.T) {
+	v := NewValidator()
+	user := validateUser{
+		Name:   "a very long name",
+		Email:  "not an email",
+		Role:   "root",
+		Age:    12,
+		Tags:   []string{"a", "b", "c"},
+		Others: []validateAddress{{City: "Paris"}, {}},
+	}
+
+	err := NewValidator(NewRule("even", func(reflect.Value, string) error { return nil })).Validate(user)
+	var validation *ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("Expected ValidationError, got: %v", err)
+	}
+
+	want := []string{
+		"name must be at most 8 characters",
+		"email must be a valid email",
+		"role must be one of [admin user]",
+		"age must be at least 18",
+		"tags must be at most 2 items",
+		"address.city is required",
+		"others[1].city is required",
+	}
+	if fmt.Sprint(validation.Fields) != fmt.Sprint(want) {
+		t.Errorf("Expected %v, got %v", want, validation.Fields)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected unknown rule to panic")
+		}
+	}()
+	v.Validate(user)
+}
+
+type validateConfig struct {
+	Mode string `json:"Mode" validate:"required,oneof=fast slow"`
+}
+
+func TestConfigBuilder_Validate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte(`{"Mode":"medium"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	app := newQuietApp(t, WithConfigFiles(file))
+	Cfg(app, ConfigBuilder(validateConfig{}))
+
+	err := app.Validate()
+	if err == nil || !strings.Contains(err.Error(), "Mode must be one of [fast slow]") {
+		t.Errorf("Expected invalid config to fail validation, got: %v", err)
+	}
+}
+
+func TestConfigBuilder_ReadErrors(t *testing.T) {
+	for _, tc := range []struct {
+		content string
+		want    string
+	}{
+		{`{"Section":{"Mode":5}}`, "cannot unmarshal number"},
+		{`{"Section":"fast"}`, "config key is not a map: Section"},
+		{`{"Other":{}}`, ""},
+	} {
+		file := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(file, []byte(tc.content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		app := newQuietApp(t, WithConfigFiles(file))
+		Cfg(app, ConfigBuilder(validateConfig{Mode: "fast"}, "Section"))
+
+		err := app.Validate()
+		if tc.want == "" && err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.content, err)
+		}
+		if tc.want != "" && (err == nil || !strings.Contains(err.Error(), "invalid config") || !strings.Contains(err.Error(), tc.want)) {
+			t.Errorf("%s: expected read error %q, got: %v", tc.content, tc.want, err)
+		}
+	}
+}
+
+func TestNew_MalformedConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte(`{"Name":`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if msg, _ := recover().(string); !strings.Contains(msg, "invalid config gofast.AppConfig") {
+			t.Fatalf("Expected malformed config to panic, got: %v", msg)
+		}
+	}()
+	New(WithConfigFiles(file), WithEnv(false))
+	t.Fatal("Expected panic")
+}
+
+type validateRequest struct {
+	Code string `json:"code" validate:"required,even"`
+	Name string `json:"name" validate:"required"`
+}
+
+func TestHandle_Validate(t *testing.T) {
+	app := newQuietApp(t)
+	Rule(app, func(*BuilderContext) ValidationRule {
+		return NewRule("even", func(value reflect.Value, _ string) error {
+			if len(value.String())%2 != 0 {
+				return errors.New("must have an even length")
+			}
+			return nil
+		})
+	})
+	ctx, release := app.NewScope(t.Context())
+	defer release()
+
+	handler := Handle(func(ctx *BuilderContext, req validateRequest) (*validateRequest, error) {
+		return &req, nil
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"code":"abc"}`))
+	handler.ServeHTTP(w, r.WithContext(ctx))
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	for _, want := range []string{
+		`{"field":"code","rule":"even","message":"must have an even length"}`,
+		`{"field":"name","rule":"required","message":"is required"}`,
+	} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("Expected body to contain %q, got %q", want, w.Body.String())
+		}
+	}
+}
+
+type validateTypo struct {
+	Code string `json:"code" validate:"even"`
+	Name string `json:"name" validate:"requird"`
+}
+
+type validateController struct{}
+
+func (c *validateController) Prefix() string {
+	return "typo"
+}
+
+func (c *validateController) Routes() http.Handler {
+	router := NewRouter()
+	router.Handle("POST /{$}", Handle(func(ctx *BuilderContext, req validateTypo) (*validateTypo, error) {
+		return &req, nil
+	}))
+	return router
+}
+
+func TestApp_ValidateRuleNames(t *testing.T) {
+	app := newQuietApp(t)
+	Rule(app, func(*BuilderContext) ValidationRule {
+		return NewRule("even", func(reflect.Value, string) error { return nil })
+	})
+	Add(app, func(*BuilderContext) *validateController {
+		return &validateController{}
+	})
+
+	err := app.Validate()
+	if err == nil || !strings.Contains(err.Error(), `unknown validation rule "requird" on gofast.validateTypo.Name`) {
+		t.Fatalf("Expected unknown rule to fail validation, got: %v", err)
+	}
+	if strings.Contains(err.Error(), `"even"`) {
+		t.Errorf("Expected registered rule to be accepted, got: %v", err)
+	}
+}